@@ -0,0 +1,76 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound    = errors.New("recurso não encontrado")
+	ErrConflict    = errors.New("conflito com o estado atual do recurso")
+	ErrValidation  = errors.New("dados inválidos")
+	ErrUnavailable = errors.New("serviço temporariamente indisponível")
+	ErrTimeout     = errors.New("tempo limite da operação excedido")
+
+	ErrPreconditionFailed   = errors.New("pré-condição falhou")
+	ErrPreconditionRequired = errors.New("pré-condição obrigatória")
+)
+
+// Error carries a descriptive message while still matching one of the
+// sentinel kinds above through errors.Is.
+type Error struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func Wrap(kind error, err error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return New(ErrNotFound, format, args...)
+}
+
+func Conflict(format string, args ...interface{}) error {
+	return New(ErrConflict, format, args...)
+}
+
+func Validation(format string, args ...interface{}) error {
+	return New(ErrValidation, format, args...)
+}
+
+func PreconditionFailed(format string, args ...interface{}) error {
+	return New(ErrPreconditionFailed, format, args...)
+}
+
+func PreconditionRequired(format string, args ...interface{}) error {
+	return New(ErrPreconditionRequired, format, args...)
+}
+
+func Unavailable(err error) error {
+	return Wrap(ErrUnavailable, err, "banco de dados indisponível")
+}
+
+func Timeout(err error) error {
+	return Wrap(ErrTimeout, err, "consulta excedeu o tempo limite")
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	// IdentityKey is the gin context key where an authentication middleware
+	// stores the authenticated identity; it takes precedence over the actor header.
+	IdentityKey = "auth_identity"
+
+	anonymousActor = "anonymous"
+
+	// The sizes of the request_id and actor columns of the history and
+	// stock tables.
+	maxRequestIDLength = 100
+	maxActorLength     = 255
+)
+
+// Middleware stores the actor and request ID in the request context so the
+// repositories can record them in the product history.
+func Middleware(actorHeader string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A request ID too long to be stored is replaced, like a missing one.
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		actor := c.GetString(IdentityKey)
+		if actor == "" {
+			actor = c.GetHeader(actorHeader)
+		}
+		if actor == "" {
+			actor = anonymousActor
+		}
+		actor = truncate(actor, maxActorLength)
+
+		ctx := WithRequestID(c.Request.Context(), requestID)
+		ctx = WithActor(ctx, actor)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
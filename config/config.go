@@ -0,0 +1,140 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Port           string
+	StorageBackend string
+	AutoMigrate    bool
+	RequireIfMatch bool
+
+	MoneyJSONFormat string
+	BaseCurrency    string
+
+	AuditActorHeader string
+
+	CursorSecret string
+
+	ImageStorage    string
+	ImageStorageDir string
+	ImageBaseURL    string
+	ImageMaxBytes   int64
+
+	FacetPriceBuckets string
+
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	ReservationTTL           time.Duration
+	ReservationMaxTTL        time.Duration
+	ReservationSweepInterval time.Duration
+
+	StockAlertNotifier   string
+	StockAlertInterval   time.Duration
+	StockAlertWebhookURL string
+	StockAlertSMTPAddr   string
+	StockAlertSMTPFrom   string
+	StockAlertSMTPTo     string
+
+	DBReadTimeout    time.Duration
+	DBWriteTimeout   time.Duration
+	DBFilterTimeout  time.Duration
+	DBSuggestTimeout time.Duration
+}
+
+func Load() Config {
+	return Config{
+		Port:           getEnv("PORT", "8080"),
+		StorageBackend: getEnv("STORAGE_BACKEND", "postgres"),
+		AutoMigrate:    getBool("DB_AUTO_MIGRATE", true),
+		RequireIfMatch: getBool("REQUIRE_IF_MATCH", false),
+
+		MoneyJSONFormat: getEnv("MONEY_JSON_FORMAT", "string"),
+		BaseCurrency:    strings.ToUpper(getEnv("BASE_CURRENCY", "BRL")),
+
+		AuditActorHeader: getEnv("AUDIT_ACTOR_HEADER", "X-Actor"),
+
+		CursorSecret: os.Getenv("CURSOR_SECRET"),
+
+		ImageStorage:    getEnv("IMAGE_STORAGE", "local"),
+		ImageStorageDir: getEnv("IMAGE_STORAGE_DIR", "uploads"),
+		ImageBaseURL:    getEnv("IMAGE_BASE_URL", "/uploads"),
+		ImageMaxBytes:   getInt64("IMAGE_MAX_BYTES", 5<<20),
+
+		FacetPriceBuckets: getEnv("FACET_PRICE_BUCKETS", "100,500,1000,5000"),
+
+		TrashRetention:     getDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval: getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		ReservationTTL:           getDuration("RESERVATION_TTL", 15*time.Minute),
+		ReservationMaxTTL:        getDuration("RESERVATION_MAX_TTL", 2*time.Hour),
+		ReservationSweepInterval: getDuration("RESERVATION_SWEEP_INTERVAL", time.Minute),
+
+		StockAlertNotifier:   getEnv("STOCK_ALERT_NOTIFIER", "log"),
+		StockAlertInterval:   getDuration("STOCK_ALERT_INTERVAL", time.Minute),
+		StockAlertWebhookURL: os.Getenv("STOCK_ALERT_WEBHOOK_URL"),
+		StockAlertSMTPAddr:   getEnv("STOCK_ALERT_SMTP_ADDR", "localhost:25"),
+		StockAlertSMTPFrom:   os.Getenv("STOCK_ALERT_SMTP_FROM"),
+		StockAlertSMTPTo:     os.Getenv("STOCK_ALERT_SMTP_TO"),
+
+		DBReadTimeout:    getDuration("DB_READ_TIMEOUT", 5*time.Second),
+		DBWriteTimeout:   getDuration("DB_WRITE_TIMEOUT", 5*time.Second),
+		DBFilterTimeout:  getDuration("DB_FILTER_TIMEOUT", 10*time.Second),
+		DBSuggestTimeout: getDuration("DB_SUGGEST_TIMEOUT", 300*time.Millisecond),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando padrão %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getInt64(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || parsed <= 0 {
+		log.Printf("Valor inválido para %s (%q), usando padrão %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando padrão %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
@@ -0,0 +1,59 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("token de paginação inválido")
+
+// Signer turns pagination positions into opaque tokens signed with
+// HMAC-SHA256, so clients cannot forge or change them.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Encode returns data and its signature as "payload.signature", both in
+// unpadded URL-safe base64.
+func (s *Signer) Encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(s.sign(data))
+}
+
+// Decode returns the data of a token produced by Encode, or ErrInvalid when
+// the token is malformed or its signature does not match.
+func (s *Signer) Decode(token string) ([]byte, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(data)) {
+		return nil, ErrInvalid
+	}
+	return data, nil
+}
+
+func (s *Signer) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// Hash returns a short digest of data, used to bind a token to the request
+// it was issued for without revealing the request.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
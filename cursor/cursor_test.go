@@ -0,0 +1,62 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	signer := NewSigner([]byte("segredo"))
+	data := []byte(`{"h":"abc","a":["5"],"l":10}`)
+
+	got, err := signer.Decode(signer.Encode(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("Decode = %s, want %s", got, data)
+	}
+}
+
+func TestDecodeRejectsTampering(t *testing.T) {
+	signer := NewSigner([]byte("segredo"))
+	token := signer.Encode([]byte(`{"h":"abc","a":["5"],"l":10}`))
+	payload, signature, _ := strings.Cut(token, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"h":"abc","a":["500"],"l":10}`))
+	flipped := []byte(signature)
+	if flipped[0] == 'A' {
+		flipped[0] = 'B'
+	} else {
+		flipped[0] = 'A'
+	}
+
+	tests := map[string]string{
+		"changed payload":     forged + "." + signature,
+		"changed signature":   payload + "." + string(flipped),
+		"missing signature":   payload,
+		"empty signature":     payload + ".",
+		"truncated signature": payload + "." + signature[:len(signature)-2],
+		"invalid base64":      payload + "!." + signature,
+		"wrong key":           NewSigner([]byte("outro segredo")).Encode([]byte(`{"h":"abc","a":["5"],"l":10}`)),
+		"empty":               "",
+	}
+
+	for name, tampered := range tests {
+		if _, err := signer.Decode(tampered); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Decode error = %v, want ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	a, b := Hash([]byte(`{"name":"a"}`)), Hash([]byte(`{"name":"b"}`))
+	if a == b {
+		t.Error("different scopes have the same hash")
+	}
+	if a != Hash([]byte(`{"name":"a"}`)) {
+		t.Error("Hash is not deterministic")
+	}
+}
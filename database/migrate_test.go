@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+)
+
+// The round trip reverts every migration, so it needs a database of its own:
+// it would wipe the one the repository tests share.
+func TestMigrationsRoundTrip(t *testing.T) {
+	url := os.Getenv("TEST_MIGRATIONS_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_MIGRATIONS_DATABASE_URL não definida")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extensions := func() map[string]bool {
+		t.Helper()
+		rows, err := db.QueryContext(ctx, `SELECT extname FROM pg_extension WHERE extname IN ('unaccent', 'pg_trgm')`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		found := make(map[string]bool)
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			found[name] = true
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return found
+	}
+
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if found := extensions(); !found["unaccent"] || !found["pg_trgm"] {
+		t.Fatalf("extensions after up = %v, want unaccent and pg_trgm", found)
+	}
+
+	// 0008 creates unaccent and 0013 pg_trgm; reverting to 0007 drops both.
+	if err := migrator.To(ctx, 7); err != nil {
+		t.Fatal(err)
+	}
+	if found := extensions(); len(found) != 0 {
+		t.Errorf("extensions after reverting to 0007 = %v, want none", found)
+	}
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
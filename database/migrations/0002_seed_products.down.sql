@@ -0,0 +1 @@
+-- Dados de exemplo não são removidos para não apagar produtos reais com o mesmo nome.
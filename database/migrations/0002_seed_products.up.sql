@@ -0,0 +1,10 @@
+INSERT INTO products (name, description, price, category, stock_quantity)
+SELECT v.name, v.description, v.price, v.category, v.stock_quantity
+FROM (VALUES
+    ('Smartphone Samsung Galaxy S23', 'Smartphone Android com 256GB de armazenamento', 2999.99, 'Eletrônicos', 50),
+    ('Notebook Dell XPS 13', 'Laptop ultrafino com processador Intel i7', 4999.99, 'Computadores', 25),
+    ('Fone de Ouvido Sony WH-1000XM4', 'Fone com cancelamento de ruído ativo', 799.99, 'Áudio', 100),
+    ('Tablet Apple iPad Air', 'Tablet com tela de 10.9 polegadas', 2499.99, 'Eletrônicos', 30),
+    ('Teclado Mecânico Logitech MX Keys', 'Teclado sem fio para produtividade', 349.99, 'Acessórios', 75)
+) AS v(name, description, price, category, stock_quantity)
+WHERE NOT EXISTS (SELECT 1 FROM products);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS product_history (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(100),
+    before JSONB,
+    after JSONB,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_history_product_id ON product_history (product_id, id);
@@ -0,0 +1,13 @@
+ALTER TABLE products ADD COLUMN price DECIMAL(10,2);
+
+UPDATE products
+SET price = pp.amount
+FROM product_prices pp
+WHERE pp.product_id = products.id AND pp.currency = 'BRL';
+
+-- Products without a BRL price cannot be represented in the single-price schema.
+UPDATE products SET price = 0 WHERE price IS NULL;
+
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+
+DROP TABLE IF EXISTS product_prices;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS product_prices (
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    amount DECIMAL(10,2) NOT NULL,
+    PRIMARY KEY (product_id, currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_prices_currency_amount ON product_prices (currency, amount);
+
+-- Existing prices had no currency; the catalog was priced in BRL.
+INSERT INTO product_prices (product_id, currency, amount)
+SELECT id, 'BRL', price FROM products;
+
+ALTER TABLE products DROP COLUMN price;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_products_gtin;
+DROP INDEX IF EXISTS idx_products_sku;
+
+ALTER TABLE products DROP COLUMN IF EXISTS gtin;
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,7 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS gtin VARCHAR(14);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products (sku);
+
+-- UPC-A, EAN-13 and GTIN-14 forms of the same code must collide.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_gtin ON products (LPAD(gtin, 14, '0'));
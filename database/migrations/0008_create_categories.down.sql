@@ -0,0 +1,13 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(100);
+
+UPDATE products p
+SET category = c.name
+FROM categories c
+WHERE c.id = p.category_id;
+
+DROP INDEX IF EXISTS idx_products_category_id;
+ALTER TABLE products DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS categories;
+
+DROP EXTENSION IF EXISTS unaccent;
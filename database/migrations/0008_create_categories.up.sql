@@ -0,0 +1,41 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(100) NOT NULL CONSTRAINT categories_slug_key UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Free-text categories that only differ by case, accents or punctuation
+-- ("Eletrônicos", "eletronicos") become a single category.
+CREATE TEMPORARY TABLE category_slugs ON COMMIT DROP AS
+SELECT DISTINCT
+    category AS original,
+    TRIM(category) AS name,
+    TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(unaccent(TRIM(category))), '[^a-z0-9]+', '-', 'g')) AS slug
+FROM products
+WHERE category IS NOT NULL;
+
+INSERT INTO categories (slug, name)
+SELECT slug, MIN(name)
+FROM category_slugs
+WHERE slug <> ''
+GROUP BY slug;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT;
+
+UPDATE products p
+SET category_id = c.id
+FROM category_slugs s
+JOIN categories c ON c.slug = s.slug
+WHERE p.category = s.original;
+
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id);
+
+ALTER TABLE products DROP COLUMN category;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64),
+    options JSONB NOT NULL,
+    stock_quantity INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_sku ON product_variants (sku);
+
+-- jsonb equality ignores key order, so {"cor":"azul","tamanho":"M"} and
+-- {"tamanho":"M","cor":"azul"} are the same combination.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variants_options ON product_variants (product_id, options);
+
+-- Serves the options @> filter of the product search.
+CREATE INDEX IF NOT EXISTS idx_product_variants_options_gin ON product_variants USING GIN (options jsonb_path_ops);
+
+CREATE TABLE IF NOT EXISTS product_variant_prices (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    amount DECIMAL(10,2) NOT NULL,
+    PRIMARY KEY (variant_id, currency)
+);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS categories_search_vector_refresh ON categories;
+DROP FUNCTION IF EXISTS categories_search_vector_refresh();
+DROP TRIGGER IF EXISTS products_search_vector_refresh ON products;
+DROP FUNCTION IF EXISTS products_search_vector_refresh();
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS products_search_vector(TEXT, TEXT, TEXT);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS portuguese_unaccent;
@@ -0,0 +1,56 @@
+-- Portuguese stemming over unaccented words, so "eletrônicos" and
+-- "eletronico" reduce to the same lexeme.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portuguese_unaccent') THEN
+        CREATE TEXT SEARCH CONFIGURATION portuguese_unaccent (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portuguese_unaccent
+            ALTER MAPPING FOR hword, hword_part, word WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+-- Name matches weigh the most, then the category name, then the description.
+CREATE OR REPLACE FUNCTION products_search_vector(product_name TEXT, product_description TEXT, category_name TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('portuguese_unaccent', COALESCE(product_name, '')), 'A')
+        || setweight(to_tsvector('portuguese_unaccent', COALESCE(category_name, '')), 'B')
+        || setweight(to_tsvector('portuguese_unaccent', COALESCE(product_description, '')), 'C')
+$$ LANGUAGE SQL STABLE;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+UPDATE products p
+SET search_vector = products_search_vector(p.name, p.description, (SELECT c.name FROM categories c WHERE c.id = p.category_id));
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+
+-- The vector includes the category name, which lives in another table, so it
+-- is kept up to date by triggers instead of a generated column.
+CREATE OR REPLACE FUNCTION products_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := products_search_vector(NEW.name, NEW.description,
+        (SELECT c.name FROM categories c WHERE c.id = NEW.category_id));
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_search_vector_refresh ON products;
+CREATE TRIGGER products_search_vector_refresh
+    BEFORE INSERT OR UPDATE OF name, description, category_id ON products
+    FOR EACH ROW EXECUTE FUNCTION products_search_vector_refresh();
+
+CREATE OR REPLACE FUNCTION categories_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    UPDATE products
+    SET search_vector = products_search_vector(name, description, NEW.name)
+    WHERE category_id = NEW.id;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS categories_search_vector_refresh ON categories;
+CREATE TRIGGER categories_search_vector_refresh
+    AFTER UPDATE OF name ON categories
+    FOR EACH ROW WHEN (OLD.name IS DISTINCT FROM NEW.name)
+    EXECUTE FUNCTION categories_search_vector_refresh();
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
+DROP EXTENSION IF EXISTS pg_trgm;
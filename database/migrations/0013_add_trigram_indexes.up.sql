@@ -0,0 +1,16 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent is only STABLE because its dictionary can change; pinning the
+-- dictionary makes it safe to use in index expressions.
+CREATE OR REPLACE FUNCTION immutable_unaccent(value TEXT) RETURNS TEXT AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, value)
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Suggestions compare the typed text with unaccented, lowercased names, so
+-- "samsumg" and "eletronico" still find "Samsung" and "Eletrônicos".
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm
+    ON products USING GIN (immutable_unaccent(lower(name)) gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm
+    ON categories USING GIN (immutable_unaccent(lower(name)) gin_trgm_ops);
@@ -0,0 +1,29 @@
+-- Like product_history, the ledger has no foreign keys so the movements of
+-- purged products and deleted variants are kept.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    variant_id INTEGER,
+    quantity INTEGER NOT NULL CHECK (quantity <> 0),
+    balance INTEGER NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('initial', 'receipt', 'sale', 'return', 'loss', 'correction')),
+    reference VARCHAR(100),
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements (product_id, id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_variant_id ON stock_movements (variant_id) WHERE variant_id IS NOT NULL;
+
+-- Opening balances, so the ledger of existing products and variants already
+-- adds up to their stock.
+INSERT INTO stock_movements (product_id, quantity, balance, reason, actor)
+SELECT id, stock_quantity, stock_quantity, 'initial', 'system'
+FROM products
+WHERE COALESCE(stock_quantity, 0) <> 0;
+
+INSERT INTO stock_movements (product_id, variant_id, quantity, balance, reason, actor)
+SELECT product_id, id, stock_quantity, stock_quantity, 'initial', 'system'
+FROM product_variants
+WHERE stock_quantity <> 0;
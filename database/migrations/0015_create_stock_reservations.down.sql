@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_reservations;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS reserved_quantity;
+ALTER TABLE products DROP COLUMN IF EXISTS reserved_quantity;
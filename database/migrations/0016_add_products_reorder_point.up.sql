@@ -0,0 +1,15 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_point INTEGER CHECK (reorder_point > 0);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_quantity INTEGER CHECK (reorder_quantity > 0);
+
+-- Last stock status the alerter handled; an alert is sent when the computed
+-- status gets worse than this one.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock_alert_status VARCHAR(20) NOT NULL DEFAULT 'in_stock'
+    CHECK (stock_alert_status IN ('in_stock', 'low', 'out_of_stock'));
+
+-- Products already out of stock when the migration runs don't trigger alerts.
+UPDATE products
+SET stock_alert_status = CASE
+    WHEN COALESCE((SELECT SUM(v.stock_quantity - v.reserved_quantity) FROM product_variants v WHERE v.product_id = products.id),
+        stock_quantity - reserved_quantity) <= 0 THEN 'out_of_stock'
+    ELSE 'in_stock'
+END;
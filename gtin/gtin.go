@@ -0,0 +1,40 @@
+package gtin
+
+// Valid reports whether code is a GTIN-8, GTIN-12 (UPC-A), GTIN-13 (EAN-13)
+// or GTIN-14 with a correct check digit.
+func Valid(code string) bool {
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		if i == len(code)-1 {
+			break
+		}
+		digit := int(c - '0')
+		// Weights alternate 3 and 1 starting from the digit next to the check digit.
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	check := (10 - sum%10) % 10
+	return int(code[len(code)-1]-'0') == check
+}
+
+// Normalize pads code to 14 digits, so the same item read as UPC-A, EAN-13 or
+// GTIN-14 compares equal.
+func Normalize(code string) string {
+	for len(code) < 14 {
+		code = "0" + code
+	}
+	return code
+}
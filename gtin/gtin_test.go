@@ -0,0 +1,70 @@
+package gtin
+
+import "testing"
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"96385074", true},       // GTIN-8
+		{"036000291452", true},   // GTIN-12 (UPC-A)
+		{"4006381333931", true},  // GTIN-13 (EAN-13)
+		{"5901234123457", true},  // GTIN-13 (EAN-13)
+		{"00012345600012", true}, // GTIN-14
+		{"10012345678902", true}, // GTIN-14
+		{"0036000291452", true},  // UPC-A written as EAN-13
+		{"96385075", false},
+		{"036000291453", false},
+		{"4006381333932", false},
+		{"10012345678903", false},
+		{"1234567", false},
+		{"123456789", false},
+		{"123456789012345", false},
+		{"4006381A33931", false},
+		{"4006381-33931", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := Valid(tt.code); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+// lpad14 is what the unique index of migration 0007 compares:
+// LPAD(gtin, 14, '0').
+func lpad14(code string) string {
+	if len(code) >= 14 {
+		return code[:14]
+	}
+	padding := ""
+	for i := len(code); i < 14; i++ {
+		padding += "0"
+	}
+	return padding + code
+}
+
+func TestNormalize(t *testing.T) {
+	for _, code := range []string{"96385074", "036000291452", "4006381333931", "00012345600012"} {
+		if got, want := Normalize(code), lpad14(code); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q as in the unique index", code, got, want)
+		}
+	}
+
+	// The same item read as UPC-A, EAN-13 and GTIN-14 must collide.
+	forms := []string{"036000291452", "0036000291452", "00036000291452"}
+	for _, code := range forms {
+		if !Valid(code) {
+			t.Errorf("Valid(%q) = false", code)
+		}
+		if got := Normalize(code); got != "00036000291452" {
+			t.Errorf("Normalize(%q) = %q, want 00036000291452", code, got)
+		}
+	}
+
+	if Normalize("96385074") == Normalize("036000291452") {
+		t.Error("different codes normalized to the same value")
+	}
+}
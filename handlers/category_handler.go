@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/repositories"
+)
+
+type CategoryHandler struct {
+	categoryRepo repositories.CategoryStore
+}
+
+func NewCategoryHandler(categoryRepo repositories.CategoryStore) *CategoryHandler {
+	return &CategoryHandler{categoryRepo: categoryRepo}
+}
+
+// findCategory looks a category up by numeric ID or by slug.
+func findCategory(ctx context.Context, categoryRepo repositories.CategoryStore, ref string) (*models.Category, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return categoryRepo.GetByID(ctx, id)
+	}
+	return categoryRepo.GetBySlug(ctx, ref)
+}
+
+// GetCategories godoc
+// @Summary Lista as categorias
+// @Description Retorna todas as categorias em ordem alfabética
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Category
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories [get]
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	categories, err := h.categoryRepo.GetAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Erro ao buscar categorias")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  categories,
+		"total": len(categories),
+	})
+}
+
+// GetCategoryTree godoc
+// @Summary Árvore de categorias
+// @Description Retorna as categorias raiz com suas subcategorias aninhadas
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.CategoryNode
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories/tree [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	categories, err := h.categoryRepo.GetAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Erro ao buscar categorias")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": buildCategoryTree(categories),
+	})
+}
+
+// GetCategory godoc
+// @Summary Busca uma categoria
+// @Description Retorna a categoria pelo ID ou slug, com o caminho desde a raiz (breadcrumbs) e as subcategorias diretas
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param id path string true "ID ou slug da categoria"
+// @Success 200 {object} models.CategoryDetail
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	category, err := findCategory(c.Request.Context(), h.categoryRepo, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "Erro ao buscar categoria")
+		return
+	}
+
+	categories, err := h.categoryRepo.GetAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Erro ao buscar categoria")
+		return
+	}
+
+	children := categoryChildren(categories)[category.ID]
+	if children == nil {
+		children = []models.Category{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": models.CategoryDetail{
+			Category:    *category,
+			Breadcrumbs: categoryBreadcrumbs(categories, category.ID),
+			Children:    children,
+		},
+	})
+}
+
+// CreateCategory godoc
+// @Summary Cria uma categoria
+// @Description Adiciona uma categoria, opcionalmente abaixo de outra; sem slug, ele é gerado a partir do nome
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param category body models.CategoryRequest true "Dados da categoria"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	req, ok := bindCategoryRequest(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryRepo.Create(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Erro ao criar categoria")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Categoria criada com sucesso",
+		"data":    category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Substitui uma categoria
+// @Description Altera nome, slug e categoria pai; uma categoria não pode ser movida para baixo de si mesma
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Param category body models.CategoryRequest true "Dados da categoria"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	req, ok := bindCategoryRequest(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryRepo.Update(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar categoria")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Categoria atualizada com sucesso",
+		"data":    category,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Remove uma categoria
+// @Description Remove uma categoria sem subcategorias e sem produtos (inclusive na lixeira)
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	if err := h.categoryRepo.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Erro ao remover categoria")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Categoria removida com sucesso",
+	})
+}
+
+// bindCategoryRequest decodes the body and derives the slug from the name when
+// it is missing. It writes a 400 response and returns false when the body is invalid.
+func bindCategoryRequest(c *gin.Context) (models.CategoryRequest, bool) {
+	var req models.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return req, false
+	}
+
+	if req.Slug == "" {
+		req.Slug = slugify(req.Name)
+		if req.Slug == "" {
+			respondError(c, apperrors.Validation("não foi possível gerar um slug a partir do nome %q", req.Name), "Erro ao validar categoria")
+			return req, false
+		}
+	}
+
+	return req, true
+}
@@ -0,0 +1,77 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func createCategory(t *testing.T, router *gin.Engine, body string) models.Category {
+	t.Helper()
+	recorder := serve(router, http.MethodPost, "/api/v1/categories", body)
+	expectStatus(t, recorder, http.StatusCreated)
+	var created struct {
+		Data models.Category `json:"data"`
+	}
+	decodeResponse(t, recorder, &created)
+	return created.Data
+}
+
+func TestCategoryCannotMoveBelowItself(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	root := createCategory(t, router, `{"name": "Decoração"}`)
+	if root.Slug != "decoracao" {
+		t.Errorf("slug = %s, want decoracao", root.Slug)
+	}
+	child := createCategory(t, router, fmt.Sprintf(`{"name": "Iluminação", "parent_id": %d}`, root.ID))
+	grandchild := createCategory(t, router, fmt.Sprintf(`{"name": "Luminárias", "parent_id": %d}`, child.ID))
+
+	for _, parent := range []int{root.ID, child.ID, grandchild.ID} {
+		path := fmt.Sprintf("/api/v1/categories/%d", root.ID)
+		body := fmt.Sprintf(`{"name": "Decoração", "parent_id": %d}`, parent)
+		expectStatus(t, serve(router, http.MethodPut, path, body), http.StatusBadRequest)
+	}
+	expectStatus(t, serve(router, http.MethodPut, fmt.Sprintf("/api/v1/categories/%d", root.ID), `{"name": "Decoração", "parent_id": 999}`), http.StatusBadRequest)
+
+	// Moving a subtree to another branch is fine.
+	other := createCategory(t, router, `{"name": "Jardim"}`)
+	path := fmt.Sprintf("/api/v1/categories/%d", child.ID)
+	expectStatus(t, serve(router, http.MethodPut, path, fmt.Sprintf(`{"name": "Iluminação", "parent_id": %d}`, other.ID)), http.StatusOK)
+
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/categories", `{"name": "Iluminação 2", "slug": "iluminacao"}`), http.StatusConflict)
+}
+
+func TestDeleteCategoryInUse(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	parent := createCategory(t, router, `{"name": "Informática"}`)
+	child := createCategory(t, router, fmt.Sprintf(`{"name": "Periféricos", "parent_id": %d}`, parent.ID))
+	parentPath := fmt.Sprintf("/api/v1/categories/%d", parent.ID)
+	childPath := fmt.Sprintf("/api/v1/categories/%d", child.ID)
+
+	expectStatus(t, serve(router, http.MethodDelete, parentPath, ""), http.StatusConflict)
+
+	recorder := serve(router, http.MethodPost, "/api/v1/products",
+		fmt.Sprintf(`{"name": "Teclado", "prices": {"BRL": "150.00"}, "stock_quantity": 3, "category_id": %d}`, child.ID))
+	expectStatus(t, recorder, http.StatusCreated)
+	var product struct {
+		Data models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &product)
+
+	// A product in the trash still belongs to the category and could be restored.
+	expectStatus(t, serve(router, http.MethodDelete, fmt.Sprintf("/api/v1/products/%d", product.Data.ID), ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, childPath, ""), http.StatusConflict)
+
+	expectStatus(t, serve(router, http.MethodPost, fmt.Sprintf("/api/v1/products/%d/restore", product.Data.ID), ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodPatch, fmt.Sprintf("/api/v1/products/%d", product.Data.ID), `{"category_id": 0}`), http.StatusOK)
+
+	expectStatus(t, serve(router, http.MethodDelete, childPath, ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodDelete, parentPath, ""), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodGet, parentPath, ""), http.StatusNotFound)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// The category tree is small, so it is always assembled from the flat list
+// returned by the store.
+
+func categoryChildren(categories []models.Category) map[int][]models.Category {
+	children := make(map[int][]models.Category)
+	for _, category := range categories {
+		parent := 0
+		if category.ParentID != nil {
+			parent = *category.ParentID
+		}
+		children[parent] = append(children[parent], category)
+	}
+	return children
+}
+
+func buildCategoryTree(categories []models.Category) []models.CategoryNode {
+	children := categoryChildren(categories)
+
+	var build func(parent int) []models.CategoryNode
+	build = func(parent int) []models.CategoryNode {
+		nodes := make([]models.CategoryNode, 0, len(children[parent]))
+		for _, category := range children[parent] {
+			nodes = append(nodes, models.CategoryNode{Category: category, Children: build(category.ID)})
+		}
+		return nodes
+	}
+
+	return build(0)
+}
+
+// categoryDescendants returns id followed by the IDs of all its subcategories.
+func categoryDescendants(categories []models.Category, id int) []int {
+	children := categoryChildren(categories)
+
+	ids := []int{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids
+}
+
+// categoryBreadcrumbs returns the path from the root category down to id.
+func categoryBreadcrumbs(categories []models.Category, id int) []models.CategorySummary {
+	byID := make(map[int]models.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	var path []models.CategorySummary
+	for current, ok := byID[id]; ok; current, ok = byID[derefID(current.ParentID)] {
+		path = append([]models.CategorySummary{current.Summary()}, path...)
+		if len(path) > len(categories) {
+			break
+		}
+	}
+	return path
+}
+
+func derefID(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
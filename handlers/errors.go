@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+)
+
+var errorStatuses = []struct {
+	kind    error
+	status  int
+	message string
+}{
+	{apperrors.ErrNotFound, http.StatusNotFound, "Recurso não encontrado"},
+	{apperrors.ErrConflict, http.StatusConflict, "Conflito com o estado atual do recurso"},
+	{apperrors.ErrValidation, http.StatusBadRequest, "Dados inválidos"},
+	{apperrors.ErrUnavailable, http.StatusServiceUnavailable, "Serviço temporariamente indisponível"},
+	{apperrors.ErrTimeout, http.StatusGatewayTimeout, "Tempo limite da operação excedido"},
+	{apperrors.ErrPreconditionFailed, http.StatusPreconditionFailed, "Recurso modificado por outra requisição"},
+	{apperrors.ErrPreconditionRequired, http.StatusPreconditionRequired, "Cabeçalho If-Match obrigatório"},
+}
+
+// respondError is the single place where domain errors become HTTP responses.
+// Errors that don't match any known kind are reported as 500 with the given message.
+func respondError(c *gin.Context, err error, message string) {
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.kind) {
+			c.JSON(e.status, gin.H{
+				"error":   e.message,
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+)
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{apperrors.NotFound("produto com ID %d não encontrado", 9), http.StatusNotFound},
+		{apperrors.Conflict("SKU já cadastrado"), http.StatusConflict},
+		{apperrors.Validation("nome obrigatório"), http.StatusBadRequest},
+		{apperrors.PreconditionFailed("versão atual é 3"), http.StatusPreconditionFailed},
+		{apperrors.PreconditionRequired("envie If-Match"), http.StatusPreconditionRequired},
+		{apperrors.Unavailable(errors.New("connection refused")), http.StatusServiceUnavailable},
+		{apperrors.Timeout(errors.New("canceling statement")), http.StatusGatewayTimeout},
+		{fmt.Errorf("ao remover: %w", apperrors.NotFound("produto")), http.StatusNotFound},
+		{errors.New("falha inesperada"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			respondError(c, tt.err, "Erro ao processar")
+
+			if recorder.Code != tt.status {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body["details"] != tt.err.Error() {
+				t.Errorf("details = %q, want %q", body["details"], tt.err.Error())
+			}
+			if tt.status == http.StatusInternalServerError && body["error"] != "Erro ao processar" {
+				t.Errorf("error = %q, want the fallback message", body["error"])
+			}
+		})
+	}
+}
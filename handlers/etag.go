@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func setETag(c *gin.Context, product *models.Product) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(product.Version)))
+}
+
+// expectedVersion reads the If-Match header. A nil version with a nil error
+// means the write may proceed regardless of the current version.
+func (h *ProductHandler) expectedVersion(c *gin.Context) (*int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		if h.options.RequireIfMatch {
+			return nil, apperrors.PreconditionRequired("envie o ETag do produto no cabeçalho If-Match")
+		}
+		return nil, nil
+	}
+
+	if header == "*" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(header, "W/") {
+		return nil, apperrors.Validation("If-Match exige um ETag forte")
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return nil, apperrors.Validation("If-Match inválido: %s", header)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return nil, apperrors.Validation("If-Match inválido: %s", header)
+	}
+
+	return &version, nil
+}
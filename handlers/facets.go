@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+const maxPriceBuckets = 20
+
+// ParsePriceBuckets reads comma separated, strictly increasing price range
+// boundaries such as "100,500,1000".
+func ParsePriceBuckets(value string) ([]money.Money, error) {
+	var boundaries []money.Money
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		boundary, err := money.Parse(part)
+		if err != nil {
+			return nil, apperrors.Validation("limite de faixa de preço inválido: %v", err)
+		}
+		if n := len(boundaries); n > 0 && boundary.Cmp(boundaries[n-1]) <= 0 {
+			return nil, apperrors.Validation("os limites das faixas de preço devem ser crescentes")
+		}
+		boundaries = append(boundaries, boundary)
+	}
+
+	if len(boundaries) == 0 {
+		return nil, apperrors.Validation("informe ao menos um limite de faixa de preço")
+	}
+	if len(boundaries) > maxPriceBuckets {
+		return nil, apperrors.Validation("no máximo %d limites de faixa de preço", maxPriceBuckets)
+	}
+	return boundaries, nil
+}
+
+// facetRequest reads the facets and price_buckets query parameters. Without
+// price_buckets the price facet uses the configured boundaries.
+func (h *ProductHandler) facetRequest(c *gin.Context) (models.FacetRequest, error) {
+	var request models.FacetRequest
+	for _, facet := range strings.Split(c.Query("facets"), ",") {
+		switch strings.TrimSpace(facet) {
+		case "":
+		case models.FacetCategory:
+			request.Category = true
+		case models.FacetPrice:
+			request.Price = true
+		case models.FacetStock:
+			request.Stock = true
+		default:
+			return request, apperrors.Validation("faceta desconhecida: %s (use %s, %s ou %s)",
+				facet, models.FacetCategory, models.FacetPrice, models.FacetStock)
+		}
+	}
+
+	request.PriceBuckets = h.options.PriceBuckets
+	if value, ok := c.GetQuery("price_buckets"); ok {
+		boundaries, err := ParsePriceBuckets(value)
+		if err != nil {
+			return request, err
+		}
+		request.PriceBuckets = boundaries
+	}
+
+	return request, nil
+}
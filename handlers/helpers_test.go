@@ -0,0 +1,81 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/cursor"
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/repositories"
+	"github.com/seuusuario/api-rest-go/routes"
+	"github.com/seuusuario/api-rest-go/storage"
+)
+
+// newTestRouter serves the API from a seeded in-memory store.
+func newTestRouter(t *testing.T, options handlers.ProductHandlerOptions) *gin.Engine {
+	t.Helper()
+	return newTestRouterWithImages(t, options, t.TempDir())
+}
+
+// newTestRouterWithImages is newTestRouter keeping the uploaded images in dir.
+func newTestRouterWithImages(t *testing.T, options handlers.ProductHandlerOptions, dir string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repo := repositories.NewMemoryProductRepository()
+	if err := repo.SeedInitialData(); err != nil {
+		t.Fatal(err)
+	}
+	images, err := storage.NewLocal(dir, "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if options.BaseCurrency == "" {
+		options.BaseCurrency = "BRL"
+	}
+	if options.Cursors == nil {
+		options.Cursors = cursor.NewSigner([]byte("test"))
+	}
+	if options.ReservationTTL == 0 {
+		options.ReservationTTL = 15 * time.Minute
+		options.ReservationMaxTTL = time.Hour
+	}
+
+	categories := repositories.NewMemoryCategoryRepository(repo)
+	router := gin.New()
+	routes.SetupRoutes(router, handlers.NewProductHandler(repo, categories, images, options), handlers.NewCategoryHandler(categories), "X-Actor")
+	return router
+}
+
+// serve sends a request with optional header name/value pairs.
+func serve(router *gin.Engine, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func decodeResponse(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response %s: %v", recorder.Body, err)
+	}
+}
+
+func expectStatus(t *testing.T, recorder *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if recorder.Code != status {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, status, recorder.Body)
+	}
+}
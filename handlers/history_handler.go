@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// historyScope binds history tokens to the product they were issued for.
+type historyScope struct {
+	ProductID int `json:"product_id"`
+}
+
+// GetProductHistory godoc
+// @Summary Histórico de alterações de um produto
+// @Description Retorna os registros de auditoria do produto (antes/depois, autor e ID da requisição) com paginação nextToken
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param next_token query string false "Token da próxima página, retornado em next_token"
+// @Param row query int false "Obsoleto, use next_token. ID do último registro (para paginação)"
+// @Param order query string false "Ordem de classificação (asc ou desc)" Enums(asc, desc)
+// @Param limit query int false "Limite de resultados por página (padrão: 10)"
+// @Success 200 {object} models.ProductHistoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/history [get]
+func (h *ProductHandler) GetProductHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	nextToken, ok := bindNextToken(c)
+	if !ok {
+		return
+	}
+
+	scope := historyScope{ProductID: id}
+	token, err := h.decodePageToken(c, scope)
+	if err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if token != nil {
+		if nextToken.Row > 0 {
+			respondError(c, apperrors.Validation("next_token não pode ser combinado com row"), "Parâmetros de paginação inválidos")
+			return
+		}
+		row, err := strconv.Atoi(token.After[0])
+		if err != nil {
+			respondError(c, apperrors.Validation("next_token inválido ou adulterado"), "Parâmetros de paginação inválidos")
+			return
+		}
+		nextToken.Row = row
+		nextToken.Order = strings.TrimPrefix(token.Sort, models.SortID+":")
+		nextToken.Limit = tokenLimit(c, token, nextToken.Limit)
+	} else if nextToken.Row > 0 {
+		deprecatedCursor(c)
+	}
+
+	history, err := h.productRepo.GetHistory(c.Request.Context(), id, nextToken)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar histórico do produto")
+		return
+	}
+
+	hasMore := len(history) > nextToken.Limit
+	if hasMore {
+		history = history[:nextToken.Limit]
+	}
+
+	response := models.ProductHistoryResponse{
+		Data:    history,
+		HasMore: hasMore,
+	}
+
+	if hasMore && len(history) > 0 {
+		last := strconv.FormatInt(history[len(history)-1].ID, 10)
+		response.NextToken = h.encodePageToken(c, scope, models.SortString(nextToken.Keys()), []string{last}, nextToken.Limit)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// Oversized audit headers must not make the write fail on the size of the
+// request_id and actor columns.
+func TestHistoryBoundsAuditHeaders(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	longID := strings.Repeat("r", 101)
+	longActor := strings.Repeat("á", 200) // 400 bytes
+	recorder := serve(router, http.MethodPost, "/api/v1/products", mouse, "X-Request-ID", longID, "X-Actor", longActor)
+	expectStatus(t, recorder, http.StatusCreated)
+	requestID := recorder.Header().Get("X-Request-ID")
+	if requestID == "" || len(requestID) > 100 {
+		t.Errorf("X-Request-ID = %q, want a new ID of at most 100 bytes", requestID)
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/products/6/history", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var history models.ProductHistoryResponse
+	decodeResponse(t, recorder, &history)
+	if len(history.Data) != 1 {
+		t.Fatalf("history = %+v, want the creation", history.Data)
+	}
+	entry := history.Data[0]
+	if entry.RequestID != requestID {
+		t.Errorf("request_id = %q, want %q", entry.RequestID, requestID)
+	}
+	if len(entry.Actor) > 255 || !utf8.ValidString(entry.Actor) || !strings.HasPrefix(longActor, entry.Actor) {
+		t.Errorf("actor = %q (%d bytes), want a prefix of at most 255 bytes", entry.Actor, len(entry.Actor))
+	}
+
+	recorder = serve(router, http.MethodPost, "/api/v1/products", `{"name": "Cabo", "prices": {"BRL": "9.90"}}`, "X-Request-ID", strings.Repeat("r", 100))
+	expectStatus(t, recorder, http.StatusCreated)
+	if got := recorder.Header().Get("X-Request-ID"); got != strings.Repeat("r", 100) {
+		t.Errorf("X-Request-ID = %q, want the one sent", got)
+	}
+}
+
+func TestHistoryOfMissingProduct(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	expectStatus(t, serve(router, http.MethodGet, "/api/v1/products/999/history", ""), http.StatusNotFound)
+
+	// Products in the trash keep their history.
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1", ""), http.StatusOK)
+	recorder := serve(router, http.MethodGet, "/api/v1/products/1/history", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var history models.ProductHistoryResponse
+	decodeResponse(t, recorder, &history)
+	if len(history.Data) == 0 || history.Data[0].Action != models.HistoryActionDelete {
+		t.Errorf("history = %+v, want the delete first", history.Data)
+	}
+
+	// A page past the last entry is empty, not missing.
+	recorder = serve(router, http.MethodGet, "/api/v1/products/1/history?row=1", "")
+	expectStatus(t, recorder, http.StatusOK)
+	decodeResponse(t, recorder, &history)
+	if len(history.Data) != 0 {
+		t.Errorf("history past the end = %+v, want empty", history.Data)
+	}
+}
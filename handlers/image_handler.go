@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// imageExtensions lists the accepted image types, detected from the file
+// content rather than from the name or the declared Content-Type.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// multipartOverhead is the room left for the multipart headers and the other
+// form fields on top of the image size limit.
+const multipartOverhead = 64 << 10
+
+func imageKey(productID int, extension string) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s%s", productID, hex.EncodeToString(token), extension), nil
+}
+
+// productAndImageIDs parses the :id and :image_id path parameters. It writes a
+// 400 response and returns false when one of them is invalid.
+func productAndImageIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return 0, 0, false
+	}
+
+	imageID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID da imagem inválido",
+		})
+		return 0, 0, false
+	}
+
+	return id, imageID, true
+}
+
+// UploadProductImage godoc
+// @Summary Envia uma imagem do produto
+// @Description Recebe uma imagem JPEG, PNG, GIF ou WebP (multipart, campo "image") e a adiciona ao final da galeria do produto. O tipo é detectado pelo conteúdo do arquivo. A primeira imagem do produto se torna a principal. Altera a versão (ETag) do produto.
+// @Tags imagens
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param image formData file true "Arquivo da imagem"
+// @Param primary formData bool false "Torna a imagem a principal do produto"
+// @Success 201 {object} models.ProductImage
+// @Header 201 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/images [post]
+func (h *ProductHandler) UploadProductImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao enviar imagem")
+		return
+	}
+
+	tooLarge := gin.H{
+		"error":   "Imagem muito grande",
+		"details": fmt.Sprintf("o tamanho máximo é de %d bytes", h.options.MaxImageBytes),
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.options.MaxImageBytes+multipartOverhead)
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Envie a imagem no campo image de um formulário multipart",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.options.MaxImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, tooLarge)
+		return
+	}
+
+	primary := false
+	if value := c.Request.FormValue("primary"); value != "" {
+		if primary, err = strconv.ParseBool(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Valor inválido para primary",
+			})
+			return
+		}
+	}
+
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		respondError(c, err, "Erro ao ler imagem")
+		return
+	}
+	contentType := http.DetectContentType(sniff[:n])
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error":   "Tipo de imagem não suportado",
+			"details": fmt.Sprintf("o arquivo enviado é %s; use JPEG, PNG, GIF ou WebP", contentType),
+		})
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		respondError(c, err, "Erro ao ler imagem")
+		return
+	}
+
+	key, err := imageKey(id, extension)
+	if err != nil {
+		respondError(c, err, "Erro ao enviar imagem")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.images.Save(ctx, key, file); err != nil {
+		respondError(c, err, "Erro ao salvar imagem")
+		return
+	}
+
+	product, err := h.productRepo.AddImage(ctx, id, models.ProductImage{
+		Key:         key,
+		ContentType: contentType,
+		Size:        header.Size,
+		Primary:     primary,
+	}, expectedVersion)
+	if err != nil {
+		if err := h.images.Delete(ctx, key); err != nil {
+			log.Printf("Erro ao remover imagem %s não registrada: %v", key, err)
+		}
+		respondError(c, err, "Erro ao enviar imagem")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	var image models.ProductImage
+	for _, candidate := range product.Images {
+		if candidate.Key == key {
+			image = candidate
+		}
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Imagem enviada com sucesso",
+		"data":    image,
+	})
+}
+
+// ReorderProductImages godoc
+// @Summary Reordena as imagens do produto
+// @Description Define a ordem da galeria; a lista precisa conter todos os IDs de imagem do produto exatamente uma vez. Altera a versão (ETag) do produto.
+// @Tags imagens
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param order body models.ImageOrderRequest true "IDs das imagens na nova ordem"
+// @Success 200 {array} models.ProductImage
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/images/order [put]
+func (h *ProductHandler) ReorderProductImages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao reordenar imagens")
+		return
+	}
+
+	var req models.ImageOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	product, err := h.productRepo.ReorderImages(c.Request.Context(), id, req.ImageIDs, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao reordenar imagens")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Ordem das imagens atualizada com sucesso",
+		"data":    product.Images,
+	})
+}
+
+// SetPrimaryProductImage godoc
+// @Summary Define a imagem principal
+// @Description Torna a imagem a principal do produto; a anterior deixa de ser. Altera a versão (ETag) do produto.
+// @Tags imagens
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param image_id path int true "ID da imagem"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Success 200 {array} models.ProductImage
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/images/{image_id}/primary [put]
+func (h *ProductHandler) SetPrimaryProductImage(c *gin.Context) {
+	id, imageID, ok := productAndImageIDs(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao definir imagem principal")
+		return
+	}
+
+	product, err := h.productRepo.SetPrimaryImage(c.Request.Context(), id, imageID, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao definir imagem principal")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Imagem principal definida com sucesso",
+		"data":    product.Images,
+	})
+}
+
+// DeleteProductImage godoc
+// @Summary Remove uma imagem do produto
+// @Description Remove a imagem e o arquivo armazenado. Se ela era a principal, a próxima da galeria assume. Altera a versão (ETag) do produto.
+// @Tags imagens
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param image_id path int true "ID da imagem"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Success 200 {object} map[string]string
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/images/{image_id} [delete]
+func (h *ProductHandler) DeleteProductImage(c *gin.Context) {
+	id, imageID, ok := productAndImageIDs(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao remover imagem")
+		return
+	}
+
+	product, removed, err := h.productRepo.DeleteImage(c.Request.Context(), id, imageID, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao remover imagem")
+		return
+	}
+
+	// The record is already gone, so a file that fails to be removed is only logged.
+	if err := h.images.Delete(c.Request.Context(), removed.Key); err != nil {
+		log.Printf("Erro ao remover arquivo da imagem %s: %v", removed.Key, err)
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Imagem removida com sucesso",
+	})
+}
@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"bytes"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+var pngImage = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+// upload sends content as the image field of a multipart form.
+func upload(t *testing.T, router *gin.Engine, path, contentType string, content []byte, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreatePart(map[string][]string{
+		"Content-Disposition": {`form-data; name="image"; filename="foto.png"`},
+		"Content-Type":        {contentType},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	return serve(router, http.MethodPost, path, body.String(), append(headers, "Content-Type", form.FormDataContentType())...)
+}
+
+// storedFiles lists the files kept in the image directory.
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestUploadRejectsUnsupportedContent(t *testing.T) {
+	dir := t.TempDir()
+	router := newTestRouterWithImages(t, handlers.ProductHandlerOptions{MaxImageBytes: 1 << 10}, dir)
+
+	tests := []struct {
+		name        string
+		contentType string
+		content     []byte
+		status      int
+	}{
+		{"text declared as png", "image/png", []byte("não sou uma imagem"), http.StatusUnsupportedMediaType},
+		{"svg", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), http.StatusUnsupportedMediaType},
+		{"pdf", "image/png", []byte("%PDF-1.4\n"), http.StatusUnsupportedMediaType},
+		{"too large", "image/png", append(pngImage, bytes.Repeat([]byte{0}, 1<<10)...), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		recorder := upload(t, router, "/api/v1/products/1/images", tt.contentType, tt.content)
+		if recorder.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, recorder.Code, tt.status, recorder.Body)
+		}
+	}
+	if files := storedFiles(t, dir); len(files) != 0 {
+		t.Errorf("rejected uploads left %v", files)
+	}
+
+	// The type comes from the content, not from the declared Content-Type.
+	recorder := upload(t, router, "/api/v1/products/1/images", "application/octet-stream", pngImage)
+	expectStatus(t, recorder, http.StatusCreated)
+	var response struct {
+		Data models.ProductImage `json:"data"`
+	}
+	decodeResponse(t, recorder, &response)
+	if response.Data.ContentType != "image/png" || filepath.Ext(response.Data.URL) != ".png" {
+		t.Errorf("image = %+v, want a png", response.Data)
+	}
+	if files := storedFiles(t, dir); len(files) != 1 {
+		t.Errorf("stored files = %v, want one", files)
+	}
+}
+
+func TestUploadRemovesFileWhenProductWriteFails(t *testing.T) {
+	dir := t.TempDir()
+	router := newTestRouterWithImages(t, handlers.ProductHandlerOptions{MaxImageBytes: 1 << 10}, dir)
+
+	// The file is saved before the product is written, so each of these
+	// failures must remove it again.
+	expectStatus(t, upload(t, router, "/api/v1/products/1/images", "image/png", pngImage, "If-Match", `"7"`), http.StatusPreconditionFailed)
+	expectStatus(t, upload(t, router, "/api/v1/products/999/images", "image/png", pngImage), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/2", ""), http.StatusOK)
+	expectStatus(t, upload(t, router, "/api/v1/products/2/images", "image/png", pngImage), http.StatusNotFound)
+
+	if files := storedFiles(t, dir); len(files) != 0 {
+		t.Errorf("failed uploads left %v", files)
+	}
+
+	recorder := serve(router, http.MethodGet, "/api/v1/products/1", "")
+	var product struct {
+		Data models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &product)
+	if len(product.Data.Images) != 0 || product.Data.Version != 1 {
+		t.Errorf("product = %+v, want no images at version 1", product.Data)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/gtin"
+)
+
+// GetProductBySKU godoc
+// @Summary Busca um produto pelo SKU
+// @Description Retorna o produto com o SKU informado
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param sku path string true "SKU do produto"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/sku/{sku} [get]
+func (h *ProductHandler) GetProductBySKU(c *gin.Context) {
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	product, err := h.productRepo.GetBySKU(c.Request.Context(), c.Param("sku"))
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	h.respondProduct(c, product, currency)
+}
+
+// GetProductByBarcode godoc
+// @Summary Busca um produto pelo código de barras
+// @Description Retorna o produto com o GTIN informado (GTIN-8, UPC-A, EAN-13 ou GTIN-14); zeros à esquerda são ignorados na comparação
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param code path string true "Código de barras (GTIN)"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/barcode/{code} [get]
+func (h *ProductHandler) GetProductByBarcode(c *gin.Context) {
+	code := c.Param("code")
+	if !gtin.Valid(code) {
+		respondError(c, apperrors.Validation("código de barras inválido: %s", code), "Erro ao buscar produto")
+		return
+	}
+
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	product, err := h.productRepo.GetByGTIN(c.Request.Context(), code)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	h.respondProduct(c, product, currency)
+}
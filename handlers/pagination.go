@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/cursor"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// pageToken is the content of the opaque next_token. Scope is a hash of the
+// request the token was issued for (route plus filters, search text or
+// product), so a token cannot be reused with other parameters or on another
+// endpoint.
+type pageToken struct {
+	Scope string   `json:"h"`
+	Sort  string   `json:"s,omitempty"`
+	After []string `json:"a"`
+	Limit int      `json:"l"`
+}
+
+// scopeHash binds scope to the route template of the request, so endpoints
+// with the same parameters, like /products and /products/filter, don't accept
+// each other's tokens.
+func scopeHash(c *gin.Context, scope interface{}) string {
+	data, _ := json.Marshal(struct {
+		Route string      `json:"r"`
+		Scope interface{} `json:"s"`
+	}{c.FullPath(), scope})
+	return cursor.Hash(data)
+}
+
+// encodePageToken signs the position after the last row of a page.
+func (h *ProductHandler) encodePageToken(c *gin.Context, scope interface{}, sort string, after []string, limit int) string {
+	data, _ := json.Marshal(pageToken{Scope: scopeHash(c, scope), Sort: sort, After: after, Limit: limit})
+	return h.options.Cursors.Encode(data)
+}
+
+// decodePageToken reads the next_token query parameter. It returns nil when
+// the parameter is missing and a validation error when the token was altered
+// or issued for a different scope.
+func (h *ProductHandler) decodePageToken(c *gin.Context, scope interface{}) (*pageToken, error) {
+	value := c.Query("next_token")
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := h.options.Cursors.Decode(value)
+	if err != nil {
+		return nil, apperrors.Validation("next_token inválido ou adulterado")
+	}
+	var token pageToken
+	if err := json.Unmarshal(data, &token); err != nil || len(token.After) == 0 {
+		return nil, apperrors.Validation("next_token inválido ou adulterado")
+	}
+	if token.Scope != scopeHash(c, scope) {
+		return nil, apperrors.Validation("next_token não corresponde aos parâmetros da busca")
+	}
+	return &token, nil
+}
+
+// tokenLimit keeps the page size of the token unless the request sets limit.
+func tokenLimit(c *gin.Context, token *pageToken, limit int) int {
+	if c.Query("limit") != "" || token.Limit <= 0 {
+		return limit
+	}
+	return token.Limit
+}
+
+// deprecatedCursor flags responses to requests still using the raw cursor
+// parameters instead of next_token.
+func deprecatedCursor(c *gin.Context) {
+	c.Header("Deprecation", "true")
+}
+
+// bindNextToken reads the row/order/limit query parameters and applies the
+// defaults. It writes a 400 response and returns false when they are invalid.
+func bindNextToken(c *gin.Context) (models.NextTokenRequest, bool) {
+	var nextToken models.NextTokenRequest
+
+	// Bind query parameters to nextToken
+	if err := c.ShouldBindQuery(&nextToken); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de paginação inválidos",
+			"details": err.Error(),
+		})
+		return nextToken, false
+	}
+
+	// Validate order parameter
+	if nextToken.Order != "" && nextToken.Order != "asc" && nextToken.Order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Parâmetro 'order' deve ser 'asc' ou 'desc'",
+		})
+		return nextToken, false
+	}
+
+	// Set default order if not provided
+	if nextToken.Order == "" {
+		nextToken.Order = "desc"
+	}
+
+	// Set default limit if not provided or invalid
+	if nextToken.Limit <= 0 {
+		nextToken.Limit = 10
+	}
+
+	// Validate limit range
+	if nextToken.Limit > 100 {
+		nextToken.Limit = 100
+	}
+
+	return nextToken, true
+}
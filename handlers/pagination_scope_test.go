@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/cursor"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// Two routes with the same handler and scope must not accept each other's
+// tokens.
+func TestPageTokenBoundToRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &ProductHandler{options: ProductHandlerOptions{Cursors: cursor.NewSigner([]byte("test"))}}
+	scope := models.ProductFilter{Currency: "BRL"}
+
+	handler := func(c *gin.Context) {
+		if _, err := h.decodePageToken(c, scope); err != nil {
+			respondError(c, err, "Parâmetros de paginação inválidos")
+			return
+		}
+		c.String(http.StatusOK, h.encodePageToken(c, scope, "id:desc", []string{"5"}, 2))
+	}
+	router := gin.New()
+	router.GET("/a", handler)
+	router.GET("/b", handler)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	token := url.QueryEscape(get("/a").Body.String())
+	if recorder := get("/a?next_token=" + token); recorder.Code != http.StatusOK {
+		t.Errorf("same route: status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := get("/b?next_token=" + token); recorder.Code != http.StatusBadRequest {
+		t.Errorf("other route: status = %d, want 400: %s", recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,73 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/cursor"
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// firstToken returns the next_token of the first page of path.
+func firstToken(t *testing.T, router *gin.Engine, path string) string {
+	t.Helper()
+	recorder := serve(router, http.MethodGet, path, "")
+	expectStatus(t, recorder, http.StatusOK)
+	var page models.ProductFilterResponse
+	decodeResponse(t, recorder, &page)
+	if page.NextToken == "" {
+		t.Fatalf("%s returned no next_token: %s", path, recorder.Body)
+	}
+	return page.NextToken
+}
+
+func TestPageTokenResumes(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+	token := firstToken(t, router, "/api/v1/products/filter?limit=2")
+
+	recorder := serve(router, http.MethodGet, "/api/v1/products/filter?next_token="+url.QueryEscape(token), "")
+	expectStatus(t, recorder, http.StatusOK)
+}
+
+func TestPageTokenRejected(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+	token := firstToken(t, router, "/api/v1/products/filter?limit=2")
+
+	payload, signature, _ := strings.Cut(token, ".")
+	tampered := []byte(payload)
+	if tampered[len(tampered)-1] == 'A' {
+		tampered[len(tampered)-1] = 'B'
+	} else {
+		tampered[len(tampered)-1] = 'A'
+	}
+	otherKey := newTestRouter(t, handlers.ProductHandlerOptions{Cursors: cursor.NewSigner([]byte("outra chave"))})
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+	}{
+		{"tampered payload", "/api/v1/products/filter", string(tampered) + "." + signature},
+		{"missing signature", "/api/v1/products/filter", payload},
+		{"signed with another key", "/api/v1/products/filter", firstToken(t, otherKey, "/api/v1/products/filter?limit=2")},
+		{"issued for another filter", "/api/v1/products/filter?name=Smartphone", token},
+		{"issued for another currency", "/api/v1/products/filter?currency=USD", token},
+		{"issued for another endpoint", "/api/v1/products", token},
+		{"issued for another sort", "/api/v1/products/filter?sort=name:asc", token},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			separator := "?"
+			if strings.Contains(tt.path, "?") {
+				separator = "&"
+			}
+			recorder := serve(router, http.MethodGet, tt.path+separator+"next_token="+url.QueryEscape(tt.token), "")
+			expectStatus(t, recorder, http.StatusBadRequest)
+		})
+	}
+}
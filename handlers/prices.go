@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+func validCurrency(currency string) bool {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return len(currency) == 3
+	}
+	return v.Var(currency, "iso4217") == nil
+}
+
+// requestedCurrency reads the currency query parameter, defaulting to the base currency.
+func (h *ProductHandler) requestedCurrency(c *gin.Context) (string, error) {
+	currency := strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+	if currency == "" {
+		return h.options.BaseCurrency, nil
+	}
+	if !validCurrency(currency) {
+		return "", apperrors.Validation("moeda inválida: %s", c.Query("currency"))
+	}
+	if !money.SupportsCurrency(currency) {
+		return "", apperrors.Validation("a moeda %s não tem duas casas decimais e não é aceita", currency)
+	}
+	return currency, nil
+}
+
+// queryAmount reads a price query parameter. Query parameters are always
+// decimal amounts, whatever MONEY_JSON_FORMAT says.
+func queryAmount(c *gin.Context, name string) (*money.Money, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(value)
+	if err != nil {
+		return nil, apperrors.Validation("%s inválido: %v", name, err)
+	}
+	return &amount, nil
+}
+
+// resolvePrices merges the base currency price sent in price into prices and
+// checks that the product ends up with a price in the base currency.
+func (h *ProductHandler) resolvePrices(price money.Money, prices map[string]money.Money) (map[string]money.Money, error) {
+	resolved, err := h.mergeBasePrice(price, prices)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := resolved[h.options.BaseCurrency]; !ok {
+		return nil, apperrors.Validation("o produto precisa ter preço na moeda base %s", h.options.BaseCurrency)
+	}
+
+	return resolved, nil
+}
+
+// mergeBasePrice copies prices adding price, when set, as the base currency price.
+func (h *ProductHandler) mergeBasePrice(price money.Money, prices map[string]money.Money) (map[string]money.Money, error) {
+	base := h.options.BaseCurrency
+
+	merged := make(map[string]money.Money, len(prices)+1)
+	for currency, amount := range prices {
+		merged[currency] = amount
+	}
+
+	if !price.IsZero() {
+		if amount, ok := merged[base]; ok && amount.Cmp(price) != 0 {
+			return nil, apperrors.Validation("price (%s) diverge de prices.%s (%s)", price, base, amount)
+		}
+		merged[base] = price
+	}
+
+	return merged, nil
+}
+
+// priceIn fills Price and Currency with the product price in currency and
+// reports whether the product has a price in it. Variants get their own price
+// in currency or, without one, the product price.
+func priceIn(product *models.Product, currency string) bool {
+	amount, ok := product.Prices[currency]
+	if !ok {
+		return false
+	}
+	product.Price = &amount
+	product.Currency = currency
+
+	// The variants are copied because the slice may be shared with the store.
+	variants := make([]models.ProductVariant, len(product.Variants))
+	for i, variant := range product.Variants {
+		price, ok := variant.Prices[currency]
+		if !ok {
+			price = amount
+		}
+		variant.Price = &price
+		variant.Currency = currency
+		variants[i] = variant
+	}
+	product.Variants = variants
+	return true
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// checkCategory rejects a product referencing a category that does not
+// exist. A nil or zero ID means no category.
+func (h *ProductHandler) checkCategory(ctx context.Context, id *int) error {
+	if id == nil || *id == 0 {
+		return nil
+	}
+
+	if _, err := h.categoryRepo.GetByID(ctx, *id); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return apperrors.Validation("categoria com ID %d não encontrada", *id)
+		}
+		return err
+	}
+	return nil
+}
+
+// categoryFilter resolves a category ID or slug into the category IDs a
+// product listing should match.
+func (h *ProductHandler) categoryFilter(ctx context.Context, ref string, includeDescendants bool) (*models.Category, []int, error) {
+	category, err := findCategory(ctx, h.categoryRepo, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !includeDescendants {
+		return category, []int{category.ID}, nil
+	}
+
+	categories, err := h.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return category, categoryDescendants(categories, category.ID), nil
+}
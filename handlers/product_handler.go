@@ -1,45 +1,82 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/cursor"
+	"github.com/seuusuario/api-rest-go/jsonpatch"
 	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
 	"github.com/seuusuario/api-rest-go/repositories"
+	"github.com/seuusuario/api-rest-go/storage"
 )
 
+type ProductHandlerOptions struct {
+	// RequireIfMatch rejects PUT, PATCH and DELETE requests without an If-Match header.
+	RequireIfMatch bool
+	// BaseCurrency is the ISO 4217 currency every product must have a price in.
+	BaseCurrency string
+	// MaxImageBytes is the largest image accepted by the upload endpoint.
+	MaxImageBytes int64
+	// PriceBuckets are the default price range boundaries of the price facet.
+	PriceBuckets []money.Money
+	// Cursors signs and verifies the next_token of paginated responses.
+	Cursors *cursor.Signer
+	// ReservationTTL is how long a reservation holds stock when the request
+	// doesn't say, and ReservationMaxTTL the longest a request may ask for.
+	ReservationTTL    time.Duration
+	ReservationMaxTTL time.Duration
+}
+
 type ProductHandler struct {
-	productRepo *repositories.ProductRepository
+	productRepo  repositories.ProductStore
+	categoryRepo repositories.CategoryStore
+	images       storage.Storage
+	options      ProductHandlerOptions
 }
 
-func NewProductHandler(productRepo *repositories.ProductRepository) *ProductHandler {
-	return &ProductHandler{productRepo: productRepo}
+func NewProductHandler(productRepo repositories.ProductStore, categoryRepo repositories.CategoryStore, images storage.Storage, options ProductHandlerOptions) *ProductHandler {
+	return &ProductHandler{productRepo: productRepo, categoryRepo: categoryRepo, images: images, options: options}
 }
 
 // GetProducts godoc
-// @Summary Lista todos os produtos
-// @Description Retorna todos os produtos cadastrados
+// @Summary Lista os produtos
+// @Description Retorna os produtos cadastrados, paginados por next_token ou, com page/page_size, por número de página
 // @Tags produtos
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Product
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Param sort query string false "Campos de ordenação com direção, separados por vírgula, ex.: price:asc,id:desc. Campos: id, name, price, created_at, updated_at e stock"
+// @Param order query string false "Ordem de classificação por ID quando não há sort (asc ou desc)" Enums(asc, desc)
+// @Param limit query int false "Limite de resultados por página (padrão: 10, máximo: 100)"
+// @Param next_token query string false "Token da próxima página, retornado em next_token"
+// @Param page query int false "Número da página (modo por número de página; não combina com next_token)"
+// @Param page_size query int false "Tamanho da página no modo por número de página (padrão: 10, máximo: 100)"
+// @Param include_total query string false "Cálculo do total: true (padrão, COUNT exato), false (omite o total) ou estimated (estimativa do planejador para conjuntos grandes)" Enums(true, false, estimated)
+// @Success 200 {object} models.ProductFilterResponse
+// @Header 200 {string} Link "Links RFC 8288 para as páginas first, prev, next e last"
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products [get]
 func (h *ProductHandler) GetProducts(c *gin.Context) {
-	products, err := h.productRepo.GetAll()
+	currency, err := h.requestedCurrency(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao buscar produtos",
-			"details": err.Error(),
-		})
+		respondError(c, err, "Erro ao buscar produtos")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":  products,
-		"total": len(products),
-	})
+	h.listProducts(c, models.ProductFilter{Currency: currency}, models.FacetRequest{}, nil)
 }
 
 // GetProduct godoc
@@ -49,10 +86,14 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do produto"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
 // @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	idStr := c.Param("id")
@@ -64,26 +105,52 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productRepo.GetByID(id)
+	currency, err := h.requestedCurrency(c)
 	if err != nil {
-		if err.Error() == "produto com ID "+idStr+" não encontrado" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Produto não encontrado",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao buscar produto",
-			"details": err.Error(),
-		})
+		respondError(c, err, "Erro ao buscar produto")
 		return
 	}
 
+	product, err := h.productRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	h.respondProduct(c, product, currency)
+}
+
+// respondProduct writes a single product priced in currency, as returned by
+// the lookup endpoints.
+func (h *ProductHandler) respondProduct(c *gin.Context, product *models.Product, currency string) {
+	if !h.present(product, currency) {
+		respondError(c, apperrors.NotFound("produto com ID %d não possui preço em %s", product.ID, currency), "Erro ao buscar produto")
+		return
+	}
+
+	setETag(c, product)
 	c.JSON(http.StatusOK, gin.H{
 		"data": product,
 	})
 }
 
+// present prepares a product for a response: it selects the prices in
+// currency, reporting whether the product has one, and fills the image URLs.
+func (h *ProductHandler) present(product *models.Product, currency string) bool {
+	if !priceIn(product, currency) {
+		return false
+	}
+
+	// The images are copied because the slice may be shared with the store.
+	images := make([]models.ProductImage, len(product.Images))
+	for i, image := range product.Images {
+		image.URL = h.images.URL(image.Key)
+		images[i] = image
+	}
+	product.Images = images
+	return true
+}
+
 // CreateProduct godoc
 // @Summary Cria um novo produto
 // @Description Adiciona um novo produto ao sistema
@@ -93,11 +160,15 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // @Param product body models.CreateProductRequest true "Dados do produto"
 // @Success 201 {object} models.Product
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var req models.CreateProductRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Dados inválidos",
 			"details": err.Error(),
@@ -105,15 +176,25 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productRepo.Create(req)
+	req.Prices, err = h.resolvePrices(req.Price, req.Prices)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao criar produto",
-			"details": err.Error(),
-		})
+		respondError(c, err, "Erro ao criar produto")
+		return
+	}
+
+	if err := h.checkCategory(c.Request.Context(), req.CategoryID); err != nil {
+		respondError(c, err, "Erro ao criar produto")
+		return
+	}
+
+	product, err := h.productRepo.Create(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Erro ao criar produto")
 		return
 	}
 
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Produto criado com sucesso",
 		"data":    product,
@@ -121,17 +202,24 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 }
 
 // UpdateProduct godoc
-// @Summary Atualiza um produto existente
-// @Description Atualiza os dados de um produto específico
+// @Summary Substitui um produto existente
+// @Description Substitui todos os dados editáveis de um produto; campos omitidos assumem o valor vazio
 // @Tags produtos
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do produto"
-// @Param product body models.UpdateProductRequest true "Dados do produto"
+// @Param If-Match header string false "ETag retornado pelo GET (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param product body models.ReplaceProductRequest true "Dados do produto"
 // @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	idStr := c.Param("id")
@@ -143,7 +231,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	var req models.UpdateProductRequest
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	var req models.ReplaceProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Dados inválidos",
@@ -152,27 +246,166 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productRepo.Update(id, req)
+	req.Prices, err = h.resolvePrices(req.Price, req.Prices)
 	if err != nil {
-		if err.Error() == "produto com ID "+idStr+" não encontrado" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Produto não encontrado",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao atualizar produto",
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	if err := h.checkCategory(c.Request.Context(), req.CategoryID); err != nil {
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	product, err := h.productRepo.Update(c.Request.Context(), id, req.ToUpdate(), expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Produto atualizado com sucesso",
+		"data":    product,
+	})
+}
+
+// PatchProduct godoc
+// @Summary Atualiza parcialmente um produto
+// @Description Aplica um JSON Merge Patch (RFC 7396, application/merge-patch+json ou application/json) ou um JSON Patch (RFC 6902, application/json-patch+json). Em merge patch, null limpa o campo.
+// @Tags produtos
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag retornado pelo GET (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param patch body object true "Documento de patch"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id} [patch]
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var applyPatch func(doc, patch []byte) ([]byte, error)
+	switch c.ContentType() {
+	case "application/json-patch+json":
+		applyPatch = jsonpatch.ApplyPatch
+	case "application/merge-patch+json", "application/json", "":
+		applyPatch = jsonpatch.ApplyMergePatch
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"error": "Content-Type deve ser application/merge-patch+json ou application/json-patch+json",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
 			"details": err.Error(),
 		})
 		return
 	}
 
+	existing, err := h.productRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produto")
+		return
+	}
+
+	if expectedVersion != nil && *expectedVersion != existing.Version {
+		respondError(c, apperrors.PreconditionFailed("produto com ID %d foi modificado (versão atual %d)", id, existing.Version), "Erro ao atualizar produto")
+		return
+	}
+
+	req, err := patchProduct(*existing, h.options.BaseCurrency, patch, applyPatch)
+	if err != nil {
+		respondError(c, err, "Erro ao aplicar patch")
+		return
+	}
+
+	req.Prices, err = h.resolvePrices(req.Price, req.Prices)
+	if err != nil {
+		respondError(c, err, "Erro ao aplicar patch")
+		return
+	}
+
+	if err := h.checkCategory(c.Request.Context(), req.CategoryID); err != nil {
+		respondError(c, err, "Erro ao aplicar patch")
+		return
+	}
+
+	// The patch was computed from the version just read, so guard the write
+	// with it even when the client did not send If-Match.
+	product, err := h.productRepo.Update(c.Request.Context(), id, req.ToUpdate(), &existing.Version)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar produto")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Produto atualizado com sucesso",
 		"data":    product,
 	})
 }
 
+// patchProduct applies the patch to the editable representation of product and
+// validates the result as if it had been sent in a PUT.
+func patchProduct(product models.Product, baseCurrency string, patch []byte, applyPatch func(doc, patch []byte) ([]byte, error)) (models.ReplaceProductRequest, error) {
+	var req models.ReplaceProductRequest
+
+	doc, err := json.Marshal(models.NewReplaceProductRequest(product, baseCurrency))
+	if err != nil {
+		return req, err
+	}
+
+	patched, err := applyPatch(doc, patch)
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return req, apperrors.Wrap(apperrors.ErrConflict, err, "patch não aplicado")
+		}
+		return req, apperrors.Wrap(apperrors.ErrValidation, err, "patch inválido")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return req, apperrors.Wrap(apperrors.ErrValidation, err, "produto resultante inválido")
+	}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return req, apperrors.Wrap(apperrors.ErrValidation, err, "produto resultante inválido")
+	}
+
+	return req, nil
+}
+
 // DeleteProduct godoc
 // @Summary Remove um produto
 // @Description Remove um produto específico do sistema
@@ -180,10 +413,16 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag retornado pelo GET (obrigatório quando REQUIRE_IF_MATCH=true)"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
@@ -195,53 +434,152 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	err = h.productRepo.Delete(id)
+	expectedVersion, err := h.expectedVersion(c)
 	if err != nil {
-		if err.Error() == "produto com ID "+idStr+" não encontrado" {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Produto não encontrado",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao remover produto",
-			"details": err.Error(),
+		respondError(c, err, "Erro ao remover produto")
+		return
+	}
+
+	err = h.productRepo.Delete(c.Request.Context(), id, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao remover produto")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Produto movido para a lixeira",
+	})
+}
+
+// GetTrash godoc
+// @Summary Lista produtos na lixeira
+// @Description Retorna os produtos removidos que ainda não foram excluídos definitivamente
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Product
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/trash [get]
+func (h *ProductHandler) GetTrash(c *gin.Context) {
+	products, err := h.productRepo.GetDeleted(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produtos na lixeira")
+		return
+	}
+	for i := range products {
+		h.present(&products[i], h.options.BaseCurrency)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  products,
+		"total": len(products),
+	})
+}
+
+// RestoreProduct godoc
+// @Summary Restaura um produto da lixeira
+// @Description Remove a marcação de exclusão de um produto que está na lixeira
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag do produto na lixeira (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
 		})
 		return
 	}
 
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao restaurar produto")
+		return
+	}
+
+	product, err := h.productRepo.Restore(c.Request.Context(), id, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao restaurar produto")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Produto removido com sucesso",
+		"message": "Produto restaurado com sucesso",
+		"data":    product,
 	})
 }
 
 // GetProductsByCategory godoc
 // @Summary Lista produtos por categoria
-// @Description Retorna todos os produtos de uma categoria específica
+// @Description Retorna os produtos de uma categoria, opcionalmente incluindo as subcategorias, paginados como em GET /products
 // @Tags produtos
 // @Accept json
 // @Produce json
-// @Param category path string true "Categoria"
-// @Success 200 {array} models.Product
+// @Param category path string true "ID ou slug da categoria"
+// @Param include_descendants query bool false "Inclui produtos das subcategorias"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Param sort query string false "Campos de ordenação com direção, separados por vírgula, ex.: price:asc,id:desc. Campos: id, name, price, created_at, updated_at e stock"
+// @Param order query string false "Ordem de classificação por ID quando não há sort (asc ou desc)" Enums(asc, desc)
+// @Param limit query int false "Limite de resultados por página (padrão: 10, máximo: 100)"
+// @Param next_token query string false "Token da próxima página, retornado em next_token"
+// @Param page query int false "Número da página (modo por número de página; não combina com next_token)"
+// @Param page_size query int false "Tamanho da página no modo por número de página (padrão: 10, máximo: 100)"
+// @Param include_total query string false "Cálculo do total: true (padrão, COUNT exato), false (omite o total) ou estimated (estimativa do planejador para conjuntos grandes)" Enums(true, false, estimated)
+// @Success 200 {object} models.ProductFilterResponse
+// @Header 200 {string} Link "Links RFC 8288 para as páginas first, prev, next e last"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products/category/{category} [get]
 func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
-	category := c.Param("category")
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produtos por categoria")
+		return
+	}
+
+	includeDescendants := false
+	if value := c.Query("include_descendants"); value != "" {
+		includeDescendants, err = strconv.ParseBool(value)
+		if err != nil {
+			respondError(c, apperrors.Validation("include_descendants inválido: %s", value), "Erro ao buscar produtos por categoria")
+			return
+		}
+	}
 
-	products, err := h.productRepo.GetByCategory(category)
+	filter := models.ProductFilter{
+		Category:           c.Param("category"),
+		IncludeDescendants: includeDescendants,
+		Currency:           currency,
+	}
+	category, categoryIDs, err := h.categoryFilter(c.Request.Context(), filter.Category, includeDescendants)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao buscar produtos por categoria",
-			"details": err.Error(),
-		})
+		respondError(c, err, "Erro ao buscar produtos por categoria")
 		return
 	}
+	filter.CategoryIDs = categoryIDs
 
-	c.JSON(http.StatusOK, gin.H{
-		"data":     products,
-		"total":    len(products),
-		"category": category,
-	})
+	summary := category.Summary()
+	h.listProducts(c, filter, models.FacetRequest{}, &summary)
 }
 
 // FindByFilter godoc
@@ -251,21 +589,37 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param name query string false "Nome do produto (busca parcial)"
-// @Param category query string false "Categoria do produto"
-// @Param min_price query number false "Preço mínimo"
-// @Param max_price query number false "Preço máximo"
-// @Param min_stock query int false "Estoque mínimo"
-// @Param max_stock query int false "Estoque máximo"
-// @Param row query int false "ID da última linha (para paginação)"
-// @Param order query string false "Ordem de classificação (asc ou desc)" Enums(asc, desc)
+// @Param category query string false "ID ou slug da categoria"
+// @Param include_descendants query bool false "Inclui produtos das subcategorias"
+// @Param tags_any query string false "Tags separadas por vírgula; retorna produtos com ao menos uma delas"
+// @Param tags_all query string false "Tags separadas por vírgula; retorna produtos com todas elas"
+// @Param options query string false "Opções de variante no formato options[nome]=valor, ex.: options[cor]=azul&options[tamanho]=M; retorna produtos com uma variante que tenha todas elas"
+// @Param currency query string false "Moeda ISO 4217 usada no retorno e nos filtros de preço (padrão: moeda base)"
+// @Param min_price query string false "Preço mínimo na moeda escolhida, sempre decimal (ex.: 100.50), seja qual for MONEY_JSON_FORMAT"
+// @Param max_price query string false "Preço máximo na moeda escolhida, sempre decimal (ex.: 100.50), seja qual for MONEY_JSON_FORMAT"
+// @Param min_stock query int false "Estoque total mínimo (soma das variantes, quando houver)"
+// @Param max_stock query int false "Estoque total máximo (soma das variantes, quando houver)"
+// @Param stock_status query string false "Situação do estoque disponível" Enums(in_stock, low, out_of_stock)
+// @Param row query int false "Obsoleto, use next_token. ID da última linha (para paginação)"
+// @Param order query string false "Ordem de classificação por ID quando não há sort (asc ou desc)" Enums(asc, desc)
+// @Param sort query string false "Campos de ordenação com direção, separados por vírgula, ex.: price:asc,id:desc. Campos: id, name, price, created_at, updated_at e stock"
+// @Param next_token query string false "Token da próxima página, retornado em next_token; vale apenas para os mesmos filtros e ordenação"
+// @Param after query []string false "Obsoleto, use next_token. Valores dos campos de ordenação na última linha da página anterior, na ordem de sort (repita o parâmetro para cada campo)" collectionFormat(multi)
 // @Param limit query int false "Limite de resultados por página (padrão: 10)"
+// @Param page query int false "Número da página (modo por número de página; não combina com next_token)"
+// @Param page_size query int false "Tamanho da página no modo por número de página (padrão: 10, máximo: 100)"
+// @Param include_total query string false "Cálculo do total: true (padrão, COUNT exato), false (omite o total) ou estimated (estimativa do planejador para conjuntos grandes)" Enums(true, false, estimated)
+// @Param facets query string false "Facetas separadas por vírgula: category, price e stock. Cada uma é contada com os filtros atuais, exceto o da própria faceta"
+// @Param price_buckets query string false "Limites crescentes das faixas de preço, separados por vírgula (padrão: FACET_PRICE_BUCKETS)"
 // @Success 200 {object} models.ProductFilterResponse
+// @Header 200 {string} Link "Links RFC 8288 para as páginas first, prev, next e last"
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
 // @Router /products/filter [get]
 func (h *ProductHandler) FindByFilter(c *gin.Context) {
 	var filter models.ProductFilter
-	var nextToken models.NextTokenRequest
 
 	// Bind query parameters to filter
 	if err := c.ShouldBindQuery(&filter); err != nil {
@@ -276,69 +630,49 @@ func (h *ProductHandler) FindByFilter(c *gin.Context) {
 		return
 	}
 
-	// Bind query parameters to nextToken
-	if err := c.ShouldBindQuery(&nextToken); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Parâmetros de paginação inválidos",
-			"details": err.Error(),
-		})
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
 		return
 	}
+	filter.Currency = currency
 
-	// Validate order parameter
-	if nextToken.Order != "" && nextToken.Order != "asc" && nextToken.Order != "desc" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Parâmetro 'order' deve ser 'asc' ou 'desc'",
-		})
+	if filter.MinPrice, err = queryAmount(c, "min_price"); err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
 		return
 	}
-
-	// Set default order if not provided
-	if nextToken.Order == "" {
-		nextToken.Order = "desc"
-	}
-
-	// Set default limit if not provided or invalid
-	if nextToken.Limit <= 0 {
-		nextToken.Limit = 10
+	if filter.MaxPrice, err = queryAmount(c, "max_price"); err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
+		return
 	}
 
-	// Validate limit range
-	if nextToken.Limit > 100 {
-		nextToken.Limit = 100
+	if filter.TagsAny, err = normalizeTags(filter.TagsAny); err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
+		return
 	}
-
-	products, total, err := h.productRepo.FindByFilter(filter, nextToken)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Erro ao buscar produtos com filtros",
-			"details": err.Error(),
-		})
+	if filter.TagsAll, err = normalizeTags(filter.TagsAll); err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
 		return
 	}
 
-	// Check if there are more results
-	hasMore := len(products) > nextToken.Limit
-	if hasMore {
-		// Remove the extra product we fetched
-		products = products[:nextToken.Limit]
+	if filter.Options, err = normalizeOptions(c.QueryMap("options")); err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
+		return
 	}
 
-	response := models.ProductFilterResponse{
-		Data:    products,
-		Total:   total,
-		HasMore: hasMore,
+	if filter.Category != "" {
+		_, filter.CategoryIDs, err = h.categoryFilter(c.Request.Context(), filter.Category, filter.IncludeDescendants)
+		if err != nil {
+			respondError(c, err, "Erro ao buscar produtos com filtros")
+			return
+		}
 	}
 
-	// Set next token if there are more results
-	if hasMore && len(products) > 0 {
-		lastProduct := products[len(products)-1]
-		response.NextToken = &models.NextTokenRequest{
-			Row:   lastProduct.ID,
-			Order: nextToken.Order,
-			Limit: nextToken.Limit,
-		}
+	facetRequest, err := h.facetRequest(c)
+	if err != nil {
+		respondError(c, err, "Parâmetros de filtro inválidos")
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	h.listProducts(c, filter, facetRequest, nil)
 }
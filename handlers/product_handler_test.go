@@ -0,0 +1,142 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+const mouse = `{"name": "Mouse sem fio", "sku": "MS-100", "prices": {"BRL": "99.90"}, "stock_quantity": 10}`
+
+func TestProductLifecycle(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	recorder := serve(router, http.MethodPost, "/api/v1/products", mouse)
+	expectStatus(t, recorder, http.StatusCreated)
+	var created struct {
+		Data models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &created)
+	if created.Data.Name != "Mouse sem fio" || created.Data.Price == nil || created.Data.Price.String() != "99.90" {
+		t.Fatalf("created = %+v", created.Data)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag = %s, want \"1\"", etag)
+	}
+	path := "/api/v1/products/" + strconv.Itoa(created.Data.ID)
+
+	recorder = serve(router, http.MethodGet, path, "")
+	expectStatus(t, recorder, http.StatusOK)
+
+	recorder = serve(router, http.MethodPut, path, `{"name": "Mouse com fio", "prices": {"BRL": "79.90"}}`, "If-Match", `"1"`)
+	expectStatus(t, recorder, http.StatusOK)
+	var updated struct {
+		Data models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &updated)
+	if updated.Data.Name != "Mouse com fio" || updated.Data.Version != 2 {
+		t.Errorf("updated = %+v", updated.Data)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("ETag = %s, want \"2\"", etag)
+	}
+
+	// The version read before the update is stale now.
+	recorder = serve(router, http.MethodDelete, path, "", "If-Match", `"1"`)
+	expectStatus(t, recorder, http.StatusPreconditionFailed)
+
+	recorder = serve(router, http.MethodDelete, path, "", "If-Match", `"2"`)
+	expectStatus(t, recorder, http.StatusOK)
+
+	recorder = serve(router, http.MethodGet, path, "")
+	expectStatus(t, recorder, http.StatusNotFound)
+}
+
+func TestProductErrors(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products", mouse), http.StatusCreated)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		headers []string
+		status  int
+	}{
+		{"missing product", http.MethodGet, "/api/v1/products/999", "", nil, http.StatusNotFound},
+		{"update of a missing product", http.MethodPut, "/api/v1/products/999", `{"name": "x", "prices": {"BRL": "1.00"}}`, nil, http.StatusNotFound},
+		{"invalid id", http.MethodGet, "/api/v1/products/abc", "", nil, http.StatusBadRequest},
+		{"duplicate SKU", http.MethodPost, "/api/v1/products", mouse, nil, http.StatusConflict},
+		{"missing name", http.MethodPost, "/api/v1/products", `{"prices": {"BRL": "1.00"}}`, nil, http.StatusBadRequest},
+		{"stale If-Match", http.MethodPut, "/api/v1/products/1", `{"name": "x", "prices": {"BRL": "1.00"}}`, []string{"If-Match", `"7"`}, http.StatusPreconditionFailed},
+		{"weak If-Match", http.MethodPut, "/api/v1/products/1", `{"name": "x", "prices": {"BRL": "1.00"}}`, []string{"If-Match", `W/"1"`}, http.StatusBadRequest},
+		{"unknown currency", http.MethodPost, "/api/v1/products", `{"name": "x", "prices": {"BRL": "1.00", "ABC": "1.00"}}`, nil, http.StatusBadRequest},
+		{"currency without decimals", http.MethodPost, "/api/v1/products", `{"name": "x", "prices": {"BRL": "1.00", "JPY": "150"}}`, nil, http.StatusBadRequest},
+		{"currency with three decimals", http.MethodPut, "/api/v1/products/1", `{"name": "x", "prices": {"BRL": "1.00", "KWD": "1.25"}}`, nil, http.StatusBadRequest},
+		{"patched currency without decimals", http.MethodPatch, "/api/v1/products/1", `{"prices": {"CLP": "900"}}`, nil, http.StatusBadRequest},
+		{"variant currency without decimals", http.MethodPost, "/api/v1/products/1/variants", `{"options": {"cor": "azul"}, "prices": {"JPY": "150"}}`, nil, http.StatusBadRequest},
+		{"listed in a currency without decimals", http.MethodGet, "/api/v1/products?currency=jpy", "", nil, http.StatusBadRequest},
+		{"listed in a currency with decimals", http.MethodGet, "/api/v1/products?currency=usd", "", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectStatus(t, serve(router, tt.method, tt.path, tt.body, tt.headers...), tt.status)
+		})
+	}
+}
+
+func TestRequireIfMatch(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{RequireIfMatch: true})
+
+	expectStatus(t, serve(router, http.MethodPut, "/api/v1/products/1", `{"name": "x", "prices": {"BRL": "1.00"}}`), http.StatusPreconditionRequired)
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1", ""), http.StatusPreconditionRequired)
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1", "", "If-Match", `"1"`), http.StatusOK)
+}
+
+// Walking the filter with next_token visits every product once, in order.
+func TestFindByFilterPages(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+	for _, sort := range []string{"", "price:asc", "name:desc"} {
+		t.Run("sort="+sort, func(t *testing.T) {
+			query := "?limit=100&sort=" + sort
+			recorder := serve(router, http.MethodGet, "/api/v1/products/filter"+query, "")
+			expectStatus(t, recorder, http.StatusOK)
+			var all models.ProductFilterResponse
+			decodeResponse(t, recorder, &all)
+
+			var visited []int
+			path := "/api/v1/products/filter?limit=2&sort=" + sort
+			for len(visited) <= len(all.Data) {
+				recorder := serve(router, http.MethodGet, path, "")
+				expectStatus(t, recorder, http.StatusOK)
+				var page models.ProductFilterResponse
+				decodeResponse(t, recorder, &page)
+				if page.Total == nil || *page.Total != len(all.Data) {
+					t.Errorf("total = %v, want %d", page.Total, len(all.Data))
+				}
+				for _, product := range page.Data {
+					visited = append(visited, product.ID)
+				}
+				if page.NextToken == "" {
+					break
+				}
+				path = "/api/v1/products/filter?next_token=" + url.QueryEscape(page.NextToken)
+			}
+
+			if len(visited) != len(all.Data) {
+				t.Fatalf("visited %v, want %d products", visited, len(all.Data))
+			}
+			for i, product := range all.Data {
+				if visited[i] != product.ID {
+					t.Fatalf("visited %v, want the order of a single page", visited)
+				}
+			}
+		})
+	}
+}
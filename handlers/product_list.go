@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// cursorParams are the position parameters dropped from the links of other pages.
+var cursorParams = []string{"next_token", "row", "after", "page"}
+
+// listProducts writes a page of the products matching filter. It serves the
+// product list, the category listing and the filter endpoint, which share the
+// cursor (next_token) and the page-number (page/page_size) modes.
+func (h *ProductHandler) listProducts(c *gin.Context, filter models.ProductFilter, facetRequest models.FacetRequest, category *models.CategorySummary) {
+	var page models.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de paginação inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	pageMode := page.Page > 0 || page.PageSize > 0
+
+	count, err := countMode(c.Query("include_total"))
+	if err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if pageMode && count == models.CountNone {
+		respondError(c, apperrors.Validation("o modo por número de página precisa do total; não use include_total=false"), "Parâmetros de paginação inválidos")
+		return
+	}
+
+	nextToken, ok := bindNextToken(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.decodePageToken(c, filter)
+	if err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if pageMode && (token != nil || nextToken.Row > 0 || len(nextToken.After) > 0) {
+		respondError(c, apperrors.Validation("page e page_size não podem ser combinados com next_token, row ou after"), "Parâmetros de paginação inválidos")
+		return
+	}
+	if token != nil {
+		if nextToken.Row > 0 || len(nextToken.After) > 0 {
+			respondError(c, apperrors.Validation("next_token não pode ser combinado com row ou after"), "Parâmetros de paginação inválidos")
+			return
+		}
+		if nextToken.Sort == "" {
+			nextToken.Sort = token.Sort
+		}
+		nextToken.After = token.After
+		nextToken.Limit = tokenLimit(c, token, nextToken.Limit)
+	} else if nextToken.Row > 0 || len(nextToken.After) > 0 {
+		deprecatedCursor(c)
+	}
+
+	if err := parseSort(&nextToken); err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if token != nil && models.SortString(nextToken.SortKeys) != token.Sort {
+		respondError(c, apperrors.Validation("next_token foi gerado para outra ordenação"), "Parâmetros de paginação inválidos")
+		return
+	}
+
+	if pageMode {
+		if page.Page == 0 {
+			page.Page = 1
+		}
+		if page.PageSize == 0 {
+			page.PageSize = 10
+		}
+		nextToken.Limit = page.PageSize
+		nextToken.Offset = (page.Page - 1) * page.PageSize
+	}
+
+	products, total, err := h.productRepo.FindByFilter(c.Request.Context(), filter, nextToken, count)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produtos")
+		return
+	}
+	for i := range products {
+		h.present(&products[i], filter.Currency)
+	}
+
+	// Check if there are more results
+	hasMore := len(products) > nextToken.Limit
+	if hasMore {
+		// Remove the extra product we fetched
+		products = products[:nextToken.Limit]
+	}
+	if products == nil {
+		products = []models.Product{}
+	}
+
+	response := models.ProductFilterResponse{
+		Data:     products,
+		HasMore:  hasMore,
+		Category: category,
+	}
+	if total != nil {
+		response.Total = &total.Count
+		response.TotalEstimated = total.Estimated
+	}
+
+	links := []string{pageLink(c, "first", nil)}
+	if pageMode {
+		totalPages := (total.Count + page.PageSize - 1) / page.PageSize
+		response.Pagination = &models.PageInfo{Page: page.Page, PageSize: page.PageSize, TotalPages: totalPages}
+
+		size := strconv.Itoa(page.PageSize)
+		links = []string{pageLink(c, "first", map[string]string{"page": "1", "page_size": size})}
+		if page.Page > 1 && totalPages > 0 {
+			prev := page.Page - 1
+			if prev > totalPages {
+				prev = totalPages
+			}
+			links = append(links, pageLink(c, "prev", map[string]string{"page": strconv.Itoa(prev), "page_size": size}))
+		}
+		if page.Page < totalPages {
+			links = append(links, pageLink(c, "next", map[string]string{"page": strconv.Itoa(page.Page + 1), "page_size": size}))
+		}
+		if totalPages > 0 {
+			links = append(links, pageLink(c, "last", map[string]string{"page": strconv.Itoa(totalPages), "page_size": size}))
+		}
+	} else if hasMore && len(products) > 0 {
+		// Set next token if there are more results
+		lastProduct := products[len(products)-1]
+		keys := nextToken.Keys()
+		after := make([]string, len(keys))
+		for i, key := range keys {
+			after[i] = models.SortValue(lastProduct, key.Field)
+		}
+		response.NextToken = h.encodePageToken(c, filter, models.SortString(keys), after, nextToken.Limit)
+		links = append(links, pageLink(c, "next", map[string]string{"next_token": response.NextToken}))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+
+	if facetRequest.Any() {
+		response.Facets, err = h.productRepo.Facets(c.Request.Context(), filter, facetRequest)
+		if err != nil {
+			respondError(c, err, "Erro ao calcular facetas")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// countMode reads the include_total parameter: true (default), false or estimated.
+func countMode(value string) (models.CountMode, error) {
+	switch value {
+	case "", "true":
+		return models.CountExact, nil
+	case "false":
+		return models.CountNone, nil
+	case "estimated":
+		return models.CountEstimated, nil
+	}
+	return "", apperrors.Validation("include_total inválido: %s (use true, false ou estimated)", value)
+}
+
+// pageLink formats an RFC 8288 link to the current request with its position
+// parameters replaced by params.
+func pageLink(c *gin.Context, rel string, params map[string]string) string {
+	target := *c.Request.URL
+	query := target.Query()
+	for _, name := range cursorParams {
+		query.Del(name)
+	}
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	target.RawQuery = query.Encode()
+	return fmt.Sprintf("<%s>; rel=\"%s\"", target.RequestURI(), rel)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// reservationID parses the :id path parameter of the reservation routes. It
+// writes a 400 response and returns false when it is invalid.
+func reservationID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID da reserva inválido",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// ReserveProductStock godoc
+// @Summary Reserva estoque de um produto
+// @Description Separa a quantidade do estoque disponível do produto, ou da variante informada, até a confirmação, o cancelamento ou a expiração da reserva. A reserva só é criada se houver estoque disponível suficiente, mesmo com pedidos simultâneos. Reservas não alteram a versão (ETag) do produto.
+// @Tags reservas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param reservation body models.ReservationRequest true "Dados da reserva"
+// @Success 201 {object} models.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/reservations [post]
+func (h *ProductHandler) ReserveProductStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var req models.ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.Reference = strings.TrimSpace(req.Reference)
+
+	ttl := h.options.ReservationTTL
+	if req.TTL > 0 {
+		ttl = time.Duration(req.TTL) * time.Second
+	}
+	if max := h.options.ReservationMaxTTL; max > 0 && ttl > max {
+		respondError(c, apperrors.Validation("ttl_seconds deve ser no máximo %d", int(max.Seconds())), "Erro ao reservar estoque")
+		return
+	}
+
+	reservation, err := h.productRepo.ReserveStock(c.Request.Context(), id, req, time.Now().Add(ttl))
+	if err != nil {
+		respondError(c, err, "Erro ao reservar estoque")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Estoque reservado com sucesso",
+		"data":    reservation,
+	})
+}
+
+// GetReservation godoc
+// @Summary Busca uma reserva
+// @Description Retorna a reserva com sua situação atual
+// @Tags reservas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da reserva"
+// @Success 200 {object} models.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /reservations/{id} [get]
+func (h *ProductHandler) GetReservation(c *gin.Context) {
+	id, ok := reservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.productRepo.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar reserva")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": reservation,
+	})
+}
+
+// ConfirmReservation godoc
+// @Summary Confirma uma reserva
+// @Description Baixa a quantidade reservada do estoque e registra uma venda no livro de estoque. Reservas expiradas não podem ser confirmadas. Altera a versão (ETag) do produto.
+// @Tags reservas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da reserva"
+// @Success 200 {object} models.StockReservation
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /reservations/{id}/confirm [post]
+func (h *ProductHandler) ConfirmReservation(c *gin.Context) {
+	id, ok := reservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, product, err := h.productRepo.ConfirmReservation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao confirmar reserva")
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reserva confirmada com sucesso",
+		"data":    reservation,
+	})
+}
+
+// CancelReservation godoc
+// @Summary Cancela uma reserva
+// @Description Devolve a quantidade reservada ao estoque disponível
+// @Tags reservas
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da reserva"
+// @Success 200 {object} models.StockReservation
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /reservations/{id}/cancel [post]
+func (h *ProductHandler) CancelReservation(c *gin.Context) {
+	id, ok := reservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.productRepo.CancelReservation(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao cancelar reserva")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reserva cancelada com sucesso",
+		"data":    reservation,
+	})
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// SearchProducts godoc
+// @Summary Busca textual de produtos
+// @Description Busca por palavras no nome, na categoria e na descrição, com radicalização em português e sem diferenciar acentos ("notebooks" encontra "Notebook"). Aceita frases entre aspas, "or" e "-" para excluir palavras. Os resultados vêm ordenados por relevância, com os trechos encontrados destacados em <mark>.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param q query string true "Texto da busca"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Param next_token query string false "Token da próxima página, retornado em next_token; vale apenas para a mesma busca"
+// @Param rank query number false "Obsoleto, use next_token. Relevância do último resultado da página anterior (paginação)"
+// @Param row query int false "Obsoleto, use next_token. ID do último resultado da página anterior (paginação)"
+// @Param limit query int false "Limite de resultados por página (padrão: 10, máximo: 100)"
+// @Success 200 {object} models.ProductSearchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/search [get]
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	var search models.ProductSearch
+	if err := c.ShouldBindQuery(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de busca inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	search.Query = strings.TrimSpace(search.Query)
+
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Parâmetros de busca inválidos")
+		return
+	}
+	search.Currency = currency
+
+	var nextToken models.SearchNextToken
+	if err := c.ShouldBindQuery(&nextToken); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de paginação inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	if nextToken.Limit <= 0 {
+		nextToken.Limit = 10
+	}
+	if nextToken.Limit > 100 {
+		nextToken.Limit = 100
+	}
+
+	token, err := h.decodePageToken(c, search)
+	if err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if token != nil {
+		if nextToken.Row > 0 {
+			respondError(c, apperrors.Validation("next_token não pode ser combinado com rank ou row"), "Parâmetros de paginação inválidos")
+			return
+		}
+		limit := tokenLimit(c, token, nextToken.Limit)
+		if nextToken, err = searchPosition(token); err != nil {
+			respondError(c, err, "Parâmetros de paginação inválidos")
+			return
+		}
+		nextToken.Limit = limit
+	} else if nextToken.Row > 0 {
+		deprecatedCursor(c)
+	}
+
+	results, err := h.productRepo.Search(c.Request.Context(), search, nextToken)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produtos")
+		return
+	}
+	for i := range results {
+		h.present(&results[i].Product, currency)
+	}
+
+	hasMore := len(results) > nextToken.Limit
+	if hasMore {
+		results = results[:nextToken.Limit]
+	}
+
+	response := models.ProductSearchResponse{
+		Data:    results,
+		HasMore: hasMore,
+	}
+	if response.Data == nil {
+		response.Data = []models.ProductSearchResult{}
+	}
+
+	if hasMore {
+		last := results[len(results)-1]
+		after := []string{strconv.FormatFloat(float64(last.Rank), 'g', -1, 32), strconv.Itoa(last.ID)}
+		response.NextToken = h.encodePageToken(c, search, "", after, nextToken.Limit)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// searchPosition reads the rank and ID of the last result from a search token.
+func searchPosition(token *pageToken) (models.SearchNextToken, error) {
+	var position models.SearchNextToken
+	if len(token.After) != 2 {
+		return position, apperrors.Validation("next_token inválido ou adulterado")
+	}
+	rank, err := strconv.ParseFloat(token.After[0], 32)
+	if err != nil {
+		return position, apperrors.Validation("next_token inválido ou adulterado")
+	}
+	row, err := strconv.Atoi(token.After[1])
+	if err != nil {
+		return position, apperrors.Validation("next_token inválido ou adulterado")
+	}
+	position.Rank = float32(rank)
+	position.Row = row
+	return position, nil
+}
+
+// SuggestProducts godoc
+// @Summary Sugestões de autocompletar
+// @Description Sugere nomes de produtos e categorias parecidos com o texto digitado, tolerando palavras incompletas, erros de digitação ("samsumg" sugere "Samsung") e acentos. As sugestões vêm da mais parecida para a menos parecida, com a similaridade em score (0 a 1).
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param prefix query string true "Texto digitado"
+// @Param limit query int false "Número de sugestões (padrão: 5, máximo: 20)"
+// @Success 200 {object} models.SuggestResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/suggest [get]
+func (h *ProductHandler) SuggestProducts(c *gin.Context) {
+	var req models.SuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de sugestão inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.Prefix = strings.TrimSpace(req.Prefix)
+	if req.Prefix == "" {
+		respondError(c, apperrors.Validation("prefix não pode ser vazio"), "Parâmetros de sugestão inválidos")
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+
+	suggestions, err := h.productRepo.Suggest(c.Request.Context(), req.Prefix, req.Limit)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar sugestões")
+		return
+	}
+	if suggestions == nil {
+		suggestions = []models.Suggestion{}
+	}
+
+	c.JSON(http.StatusOK, models.SuggestResponse{Data: suggestions})
+}
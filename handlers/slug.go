@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	slugPattern   = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// slugify mirrors the expression used by the categories migration: accents are
+// removed, the text is lowercased and every other run of characters becomes "-".
+func slugify(s string) string {
+	unaccented, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		unaccented = s
+	}
+	slug := slugSeparator.ReplaceAllString(strings.ToLower(strings.TrimSpace(unaccented)), "-")
+	return strings.Trim(slug, "-")
+}
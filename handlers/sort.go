@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+const maxSortFields = 5
+
+// parseSort fills nextToken.SortKeys from the sort and after parameters. The
+// id is appended as the last key when missing so that the order is total and
+// the keyset cursor never skips or repeats rows.
+func parseSort(nextToken *models.NextTokenRequest) error {
+	if nextToken.Sort == "" {
+		if len(nextToken.After) > 0 {
+			return apperrors.Validation("after só pode ser usado junto com sort")
+		}
+		return nil
+	}
+
+	var keys []models.SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(nextToken.Sort, ",") {
+		field, direction, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if !validSortField(field) {
+			return apperrors.Validation("campo de ordenação inválido: %q (use %s)", field, strings.Join(models.SortFields, ", "))
+		}
+		if seen[field] {
+			return apperrors.Validation("campo de ordenação repetido: %s", field)
+		}
+		seen[field] = true
+
+		switch direction {
+		case "", "asc":
+			keys = append(keys, models.SortKey{Field: field})
+		case "desc":
+			keys = append(keys, models.SortKey{Field: field, Desc: true})
+		default:
+			return apperrors.Validation("direção de ordenação inválida: %q (use asc ou desc)", direction)
+		}
+	}
+	if len(keys) > maxSortFields {
+		return apperrors.Validation("no máximo %d campos de ordenação", maxSortFields)
+	}
+	if !seen[models.SortID] {
+		keys = append(keys, models.SortKey{Field: models.SortID, Desc: keys[len(keys)-1].Desc})
+	}
+
+	after := nextToken.After
+	if len(after) == 0 && nextToken.Row > 0 {
+		if len(keys) > 1 {
+			return apperrors.Validation("com sort, informe a posição em after em vez de row")
+		}
+		after = []string{models.SortValue(models.Product{ID: nextToken.Row}, models.SortID)}
+	}
+	if len(after) > 0 {
+		if len(after) != len(keys) {
+			return apperrors.Validation("after deve ter um valor para cada campo de ordenação (%s)", models.SortString(keys))
+		}
+		for i := range keys {
+			value, err := models.ParseSortValue(keys[i].Field, after[i])
+			if err != nil {
+				return apperrors.Validation("valor inválido em after para %s: %q", keys[i].Field, after[i])
+			}
+			keys[i].After = value
+		}
+	}
+
+	nextToken.SortKeys = keys
+	return nil
+}
+
+func validSortField(field string) bool {
+	for _, valid := range models.SortFields {
+		if field == valid {
+			return true
+		}
+	}
+	return false
+}
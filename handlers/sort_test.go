@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		sort  string
+		after []string
+		want  []models.SortKey
+	}{
+		{
+			sort: "name",
+			want: []models.SortKey{{Field: "name"}, {Field: "id"}},
+		},
+		{
+			sort: "price:asc,name:desc",
+			want: []models.SortKey{{Field: "price"}, {Field: "name", Desc: true}, {Field: "id", Desc: true}},
+		},
+		{
+			sort: "id:asc,stock:desc",
+			want: []models.SortKey{{Field: "id"}, {Field: "stock", Desc: true}},
+		},
+		{
+			sort:  "price:desc,name",
+			after: []string{"10.5", "Água", "7"},
+			want: []models.SortKey{
+				{Field: "price", Desc: true, After: money.MustParse("10.50")},
+				{Field: "name", After: "Água"},
+				{Field: "id", After: 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			nextToken := models.NextTokenRequest{Sort: tt.sort, After: tt.after}
+			if err := parseSort(&nextToken); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(nextToken.SortKeys, tt.want) {
+				t.Errorf("SortKeys = %+v, want %+v", nextToken.SortKeys, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSortErrors(t *testing.T) {
+	tests := []models.NextTokenRequest{
+		{Sort: "color"},
+		{Sort: "name:up"},
+		{Sort: "name,name:desc"},
+		{Sort: "id,name,price,stock,created_at,updated_at"},
+		{After: []string{"5"}},
+		{Sort: "price,name", After: []string{"10.00", "x"}},
+		{Sort: "price", After: []string{"dez", "1"}},
+		{Sort: "price", Row: 5},
+	}
+
+	for _, nextToken := range tests {
+		if err := parseSort(&nextToken); !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("parseSort(%+v) error = %v, want validation error", nextToken, err)
+		}
+	}
+}
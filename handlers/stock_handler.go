@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// stockScope binds movement tokens to the product and filter they were issued for.
+type stockScope struct {
+	ProductID int                        `json:"product_id"`
+	Filter    models.StockMovementFilter `json:"filter"`
+}
+
+// RecordStockMovement godoc
+// @Summary Registra um movimento de estoque
+// @Description Soma a quantidade (positiva para entradas, negativa para saídas) ao estoque do produto ou da variante informada e registra o movimento no livro de estoque, na mesma transação. Recebimentos (receipt) e devoluções (return) devem ser positivos, vendas (sale) e perdas (loss) negativos e correções (correction) podem ter qualquer sinal. Movimentos que deixariam o estoque negativo são rejeitados. O If-Match é opcional, mesmo com REQUIRE_IF_MATCH=true, já que o movimento é aplicado sobre o saldo atual. Altera a versão (ETag) do produto.
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag do produto"
+// @Param movement body models.StockMovementRequest true "Dados do movimento"
+// @Success 201 {object} models.StockMovement
+// @Header 201 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/stock/movements [post]
+func (h *ProductHandler) RecordStockMovement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	// Concurrent movements don't overwrite each other, so a missing If-Match
+	// is accepted even when it is required for the other writes.
+	var expectedVersion *int
+	if c.GetHeader("If-Match") != "" {
+		if expectedVersion, err = h.expectedVersion(c); err != nil {
+			respondError(c, err, "Erro ao registrar movimento de estoque")
+			return
+		}
+	}
+
+	var req models.StockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+	req.Reference = strings.TrimSpace(req.Reference)
+
+	if !models.StockDirectionValid(req.Reason, req.Quantity) {
+		direction := "positiva"
+		if req.Reason == models.StockReasonSale || req.Reason == models.StockReasonLoss {
+			direction = "negativa"
+		}
+		respondError(c, apperrors.Validation("movimentos do tipo %s devem ter quantidade %s", req.Reason, direction), "Erro ao registrar movimento de estoque")
+		return
+	}
+
+	movement, product, err := h.productRepo.RecordStockMovement(c.Request.Context(), id, req, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao registrar movimento de estoque")
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Movimento de estoque registrado com sucesso",
+		"data":    movement,
+	})
+}
+
+// GetStockMovements godoc
+// @Summary Lista os movimentos de estoque de um produto
+// @Description Retorna o livro de estoque do produto, do mais recente para o mais antigo por padrão, com paginação nextToken
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param reason query string false "Filtra pelo motivo do movimento" Enums(initial, receipt, sale, return, loss, correction)
+// @Param variant_id query int false "Filtra pelos movimentos de uma variante"
+// @Param next_token query string false "Token da próxima página, retornado em next_token"
+// @Param order query string false "Ordem de classificação (asc ou desc)" Enums(asc, desc)
+// @Param limit query int false "Limite de resultados por página (padrão: 10)"
+// @Success 200 {object} models.StockMovementResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/stock/movements [get]
+func (h *ProductHandler) GetStockMovements(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var filter models.StockMovementFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros de filtro inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	nextToken, ok := bindNextToken(c)
+	if !ok {
+		return
+	}
+
+	scope := stockScope{ProductID: id, Filter: filter}
+	token, err := h.decodePageToken(c, scope)
+	if err != nil {
+		respondError(c, err, "Parâmetros de paginação inválidos")
+		return
+	}
+	if token != nil {
+		row, err := strconv.Atoi(token.After[0])
+		if err != nil {
+			respondError(c, apperrors.Validation("next_token inválido ou adulterado"), "Parâmetros de paginação inválidos")
+			return
+		}
+		nextToken.Row = row
+		nextToken.Order = strings.TrimPrefix(token.Sort, models.SortID+":")
+		nextToken.Limit = tokenLimit(c, token, nextToken.Limit)
+	}
+
+	movements, err := h.productRepo.GetStockMovements(c.Request.Context(), id, filter, nextToken)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar movimentos de estoque")
+		return
+	}
+
+	hasMore := len(movements) > nextToken.Limit
+	if hasMore {
+		movements = movements[:nextToken.Limit]
+	}
+
+	response := models.StockMovementResponse{
+		Data:    movements,
+		HasMore: hasMore,
+	}
+	if response.Data == nil {
+		response.Data = []models.StockMovement{}
+	}
+
+	if hasMore && len(movements) > 0 {
+		last := strconv.FormatInt(movements[len(movements)-1].ID, 10)
+		response.NextToken = h.encodePageToken(c, scope, models.SortString(nextToken.Keys()), []string{last}, nextToken.Limit)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStockReconciliation godoc
+// @Summary Confere o estoque de um produto com o livro de estoque
+// @Description Compara o estoque do produto e de cada variante com a soma dos seus movimentos
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Success 200 {object} models.StockReconciliationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/stock/reconciliation [get]
+func (h *ProductHandler) GetStockReconciliation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	h.reconcile(c, models.ReconciliationFilter{ProductID: id})
+}
+
+// GetInventoryReconciliation godoc
+// @Summary Confere todo o estoque com o livro de estoque
+// @Description Lista os produtos e variantes cujo estoque difere da soma dos seus movimentos; consistent é true quando não há divergências
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.StockReconciliationResponse
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /inventory/reconciliation [get]
+func (h *ProductHandler) GetInventoryReconciliation(c *gin.Context) {
+	h.reconcile(c, models.ReconciliationFilter{DiscrepanciesOnly: true})
+}
+
+func (h *ProductHandler) reconcile(c *gin.Context, filter models.ReconciliationFilter) {
+	results, err := h.productRepo.ReconcileStock(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err, "Erro ao conferir estoque")
+		return
+	}
+
+	response := models.StockReconciliationResponse{
+		Data:       results,
+		Consistent: true,
+	}
+	if response.Data == nil {
+		response.Data = []models.StockReconciliation{}
+	}
+	for _, result := range results {
+		if result.Difference != 0 {
+			response.Consistent = false
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLowStock godoc
+// @Summary Lista os produtos com estoque baixo
+// @Description Lista os produtos cujo estoque disponível chegou ao ponto de reposição (low) ou acabou (out_of_stock), primeiro os sem estoque e depois os mais abaixo do ponto de reposição
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param status query string false "Situação do estoque" Enums(low, out_of_stock)
+// @Success 200 {object} models.LowStockResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /inventory/low-stock [get]
+func (h *ProductHandler) GetLowStock(c *gin.Context) {
+	var filter models.LowStockFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Parâmetros inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	products, err := h.productRepo.GetLowStock(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar produtos com estoque baixo")
+		return
+	}
+
+	response := models.LowStockResponse{Data: make([]models.LowStockItem, 0, len(products))}
+	for _, product := range products {
+		response.Data = append(response.Data, models.NewLowStockItem(product))
+	}
+	response.Total = len(response.Data)
+
+	c.JSON(http.StatusOK, response)
+}
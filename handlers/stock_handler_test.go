@@ -0,0 +1,35 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// Setting stock_quantity with PUT goes through the ledger as a correction.
+func TestPutKeepsLedgerBalanced(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	recorder := serve(router, http.MethodPut, "/api/v1/products/1", `{"name": "Smartphone", "prices": {"BRL": "2999.99"}, "stock_quantity": 42}`)
+	expectStatus(t, recorder, http.StatusOK)
+	recorder = serve(router, http.MethodPost, "/api/v1/products/1/stock/movements", `{"quantity": -2, "reason": "sale"}`)
+	expectStatus(t, recorder, http.StatusCreated)
+
+	recorder = serve(router, http.MethodGet, "/api/v1/products/1/stock/movements?reason=correction", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var movements models.StockMovementResponse
+	decodeResponse(t, recorder, &movements)
+	if len(movements.Data) != 1 || movements.Data[0].Quantity != -8 || movements.Data[0].Balance != 42 {
+		t.Errorf("corrections = %+v, want one of -8 leaving 42", movements.Data)
+	}
+
+	recorder = serve(router, http.MethodGet, "/api/v1/products/1/stock/reconciliation", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var reconciliation models.StockReconciliationResponse
+	decodeResponse(t, recorder, &reconciliation)
+	if !reconciliation.Consistent || len(reconciliation.Data) != 1 || reconciliation.Data[0].StockQuantity != 40 {
+		t.Errorf("reconciliation = %+v, want 40 units matching the ledger", reconciliation)
+	}
+}
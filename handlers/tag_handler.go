@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// normalizeTags turns tags into unique lowercase slugs. Each value may also
+// hold several comma-separated tags, as sent in the tags_any and tags_all filters.
+func normalizeTags(values []string) ([]string, error) {
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, value := range values {
+		for _, raw := range strings.Split(value, ",") {
+			if strings.TrimSpace(raw) == "" {
+				continue
+			}
+			tag := slugify(raw)
+			if tag == "" || len(tag) > 50 {
+				return nil, apperrors.Validation("tag inválida: %q", raw)
+			}
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// AddProductTags godoc
+// @Summary Adiciona tags a um produto
+// @Description Associa as tags ao produto, criando as que ainda não existem. Os nomes são normalizados (minúsculas, sem acentos, espaços viram "-"); tags já associadas são ignoradas.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag retornado pelo GET (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param tags body models.ProductTagsRequest true "Tags a adicionar"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/tags [post]
+func (h *ProductHandler) AddProductTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao adicionar tags")
+		return
+	}
+
+	var req models.ProductTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err == nil && len(tags) == 0 {
+		err = apperrors.Validation("informe ao menos uma tag")
+	}
+	if err != nil {
+		respondError(c, err, "Erro ao adicionar tags")
+		return
+	}
+
+	product, err := h.productRepo.AddTags(c.Request.Context(), id, tags, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao adicionar tags")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tags adicionadas com sucesso",
+		"data":    product,
+	})
+}
+
+// RemoveProductTag godoc
+// @Summary Remove uma tag de um produto
+// @Description Desassocia a tag do produto
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param tag path string true "Nome da tag"
+// @Param If-Match header string false "ETag retornado pelo GET (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Success 200 {object} models.Product
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/tags/{tag} [delete]
+func (h *ProductHandler) RemoveProductTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao remover tag")
+		return
+	}
+
+	product, err := h.productRepo.RemoveTag(c.Request.Context(), id, slugify(c.Param("tag")), expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao remover tag")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tag removida com sucesso",
+		"data":    product,
+	})
+}
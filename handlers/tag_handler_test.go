@@ -0,0 +1,89 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func TestAddProductTagsNormalizes(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"lowercase", []string{"Promo"}, []string{"promo"}},
+		{"accents and spaces", []string{" Promoção de Verão "}, []string{"promocao-de-verao"}},
+		{"comma separated", []string{"novo,Oferta"}, []string{"novo", "oferta"}},
+		{"duplicates after normalizing", []string{"Black Friday", "black-friday", "BLACK FRIDAY"}, []string{"black-friday"}},
+		{"sorted", []string{"zeta", "alfa"}, []string{"alfa", "zeta"}},
+	}
+
+	for _, tt := range tests {
+		router := newTestRouter(t, handlers.ProductHandlerOptions{})
+		body, _ := json.Marshal(map[string][]string{"tags": tt.tags})
+		recorder := serve(router, http.MethodPost, "/api/v1/products/1/tags", string(body))
+		expectStatus(t, recorder, http.StatusOK)
+
+		var response struct {
+			Data models.Product `json:"data"`
+		}
+		decodeResponse(t, recorder, &response)
+		if !reflect.DeepEqual(response.Data.Tags, tt.want) {
+			t.Errorf("%s: tags = %v, want %v", tt.name, response.Data.Tags, tt.want)
+		}
+	}
+
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+	for _, body := range []string{`{"tags": ["!!!"]}`, `{"tags": [","]}`, `{"tags": []}`} {
+		expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/tags", body), http.StatusBadRequest)
+	}
+}
+
+func TestProductTagsAreUnique(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	expectTags := func(body string, want ...string) {
+		t.Helper()
+		recorder := serve(router, http.MethodPost, "/api/v1/products/1/tags", body)
+		expectStatus(t, recorder, http.StatusOK)
+		var response struct {
+			Data models.Product `json:"data"`
+		}
+		decodeResponse(t, recorder, &response)
+		if !reflect.DeepEqual(response.Data.Tags, want) {
+			t.Errorf("tags = %v, want %v", response.Data.Tags, want)
+		}
+	}
+
+	expectTags(`{"tags": ["promoção", "novo"]}`, "novo", "promocao")
+	expectTags(`{"tags": ["Promoção", "NOVO", "oferta"]}`, "novo", "oferta", "promocao")
+
+	// The tag in the path is normalized the same way.
+	recorder := serve(router, http.MethodDelete, "/api/v1/products/1/tags/Promo%C3%A7%C3%A3o", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var response struct {
+		Data models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &response)
+	if want := []string{"novo", "oferta"}; !reflect.DeepEqual(response.Data.Tags, want) {
+		t.Errorf("tags = %v, want %v", response.Data.Tags, want)
+	}
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1/tags/promocao", ""), http.StatusNotFound)
+
+	// Tags belong to each product, so another product can use the same ones.
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/2/tags", `{"tags": ["novo"]}`), http.StatusOK)
+	recorder = serve(router, http.MethodGet, "/api/v1/products/filter?tags_any=Novo", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var list struct {
+		Data []models.Product `json:"data"`
+	}
+	decodeResponse(t, recorder, &list)
+	if len(list.Data) != 2 {
+		t.Errorf("tags_any=Novo returned %d products, want 2", len(list.Data))
+	}
+}
@@ -0,0 +1,46 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func TestTrashAndRestore(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1", "", "If-Match", `"1"`), http.StatusOK)
+	expectStatus(t, serve(router, http.MethodGet, "/api/v1/products/1", ""), http.StatusNotFound)
+	expectStatus(t, serve(router, http.MethodDelete, "/api/v1/products/1", ""), http.StatusNotFound)
+
+	recorder := serve(router, http.MethodGet, "/api/v1/products/trash", "")
+	expectStatus(t, recorder, http.StatusOK)
+	var trash struct {
+		Data  []models.Product `json:"data"`
+		Total int              `json:"total"`
+	}
+	decodeResponse(t, recorder, &trash)
+	if trash.Total != 1 || len(trash.Data) != 1 || trash.Data[0].ID != 1 || trash.Data[0].DeletedAt == nil {
+		t.Fatalf("trash = %+v, want product 1", trash)
+	}
+
+	// The delete bumped the version to 2.
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/restore", "", "If-Match", `"1"`), http.StatusPreconditionFailed)
+	recorder = serve(router, http.MethodPost, "/api/v1/products/1/restore", "", "If-Match", `"2"`)
+	expectStatus(t, recorder, http.StatusOK)
+	if etag := recorder.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag = %s, want \"3\"", etag)
+	}
+	expectStatus(t, serve(router, http.MethodGet, "/api/v1/products/1", ""), http.StatusOK)
+
+	recorder = serve(router, http.MethodGet, "/api/v1/products/trash", "")
+	decodeResponse(t, recorder, &trash)
+	if trash.Total != 0 {
+		t.Errorf("trash = %+v, want empty", trash)
+	}
+
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/2/restore", ""), http.StatusConflict)
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/999/restore", ""), http.StatusNotFound)
+}
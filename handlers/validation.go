@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/seuusuario/api-rest-go/gtin"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Money is a struct, so tags like required and gt=0 would not apply to it;
+	// validate it through its amount in minor units instead.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(money.Money); ok {
+			return m.Cents()
+		}
+		return nil
+	}, money.Money{})
+
+	v.RegisterValidation("sku", func(fl validator.FieldLevel) bool {
+		return skuPattern.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("gtin", func(fl validator.FieldLevel) bool {
+		return gtin.Valid(fl.Field().String())
+	})
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+	// Prices are Money, so only ISO 4217 currencies with two decimal places.
+	v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		currency := fl.Field().String()
+		return v.Var(currency, "iso4217") == nil && money.SupportsCurrency(currency)
+	})
+}
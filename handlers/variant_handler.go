@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// normalizeOptions turns option names into lowercase slugs, so "Cor" and "cor"
+// are the same axis, and trims the values.
+func normalizeOptions(options map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(options))
+	for name, value := range options {
+		key := slugify(name)
+		value = strings.TrimSpace(value)
+		if key == "" || value == "" {
+			return nil, apperrors.Validation("opção inválida: %q=%q", name, value)
+		}
+		if _, ok := normalized[key]; ok {
+			return nil, apperrors.Validation("opção repetida: %s", key)
+		}
+		normalized[key] = value
+	}
+	return normalized, nil
+}
+
+// findVariant returns the variant of product with exactly the given options.
+func findVariant(product *models.Product, options map[string]string) (models.ProductVariant, bool) {
+	for _, variant := range product.Variants {
+		if len(variant.Options) == len(options) && variant.MatchesOptions(options) {
+			return variant, true
+		}
+	}
+	return models.ProductVariant{}, false
+}
+
+func variantByID(product *models.Product, id int) (models.ProductVariant, error) {
+	for _, variant := range product.Variants {
+		if variant.ID == id {
+			return variant, nil
+		}
+	}
+	return models.ProductVariant{}, apperrors.NotFound("variante com ID %d não encontrada no produto %d", id, product.ID)
+}
+
+// productAndVariantIDs parses the :id and :variant_id path parameters. It
+// writes a 400 response and returns false when one of them is invalid.
+func productAndVariantIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return 0, 0, false
+	}
+
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID da variante inválido",
+		})
+		return 0, 0, false
+	}
+
+	return id, variantID, true
+}
+
+// bindVariantRequest decodes the body, normalizes the options and folds the
+// base currency price into Prices. It writes the error response and returns
+// false when the body is invalid.
+func (h *ProductHandler) bindVariantRequest(c *gin.Context) (models.VariantRequest, bool) {
+	var req models.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Dados inválidos",
+			"details": err.Error(),
+		})
+		return req, false
+	}
+
+	var err error
+	if req.Options, err = normalizeOptions(req.Options); err != nil {
+		respondError(c, err, "Erro ao validar variante")
+		return req, false
+	}
+	if req.Prices, err = h.mergeBasePrice(req.Price, req.Prices); err != nil {
+		respondError(c, err, "Erro ao validar variante")
+		return req, false
+	}
+
+	return req, true
+}
+
+// GetProductVariants godoc
+// @Summary Lista as variantes de um produto
+// @Description Retorna as variantes do produto com o preço na moeda escolhida; variantes sem preço próprio nessa moeda usam o preço do produto
+// @Tags variantes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Success 200 {array} models.ProductVariant
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/variants [get]
+func (h *ProductHandler) GetProductVariants(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	product, ok := h.pricedProduct(c, id)
+	if !ok {
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"data":    product.Variants,
+		"options": product.Options,
+		"total":   len(product.Variants),
+	})
+}
+
+// GetProductVariant godoc
+// @Summary Busca uma variante
+// @Description Retorna uma variante do produto com o preço na moeda escolhida
+// @Tags variantes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param variant_id path int true "ID da variante"
+// @Param currency query string false "Moeda ISO 4217 do preço retornado (padrão: moeda base)"
+// @Success 200 {object} models.ProductVariant
+// @Header 200 {string} ETag "Versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/variants/{variant_id} [get]
+func (h *ProductHandler) GetProductVariant(c *gin.Context) {
+	id, variantID, ok := productAndVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	product, ok := h.pricedProduct(c, id)
+	if !ok {
+		return
+	}
+
+	variant, err := variantByID(product, variantID)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar variante")
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"data": variant,
+	})
+}
+
+// pricedProduct loads the product with prices in the requested currency. It
+// writes the error response and returns false when that fails.
+func (h *ProductHandler) pricedProduct(c *gin.Context, id int) (*models.Product, bool) {
+	currency, err := h.requestedCurrency(c)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar variantes")
+		return nil, false
+	}
+
+	product, err := h.productRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Erro ao buscar variantes")
+		return nil, false
+	}
+
+	if !h.present(product, currency) {
+		respondError(c, apperrors.NotFound("produto com ID %d não possui preço em %s", id, currency), "Erro ao buscar variantes")
+		return nil, false
+	}
+
+	return product, true
+}
+
+// CreateProductVariant godoc
+// @Summary Cria uma variante
+// @Description Adiciona uma variante ao produto. Todas as variantes de um produto usam os mesmos nomes de opção, e cada combinação de valores só pode aparecer uma vez. Altera a versão (ETag) do produto.
+// @Tags variantes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param variant body models.VariantRequest true "Dados da variante"
+// @Success 201 {object} models.ProductVariant
+// @Header 201 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/variants [post]
+func (h *ProductHandler) CreateProductVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao criar variante")
+		return
+	}
+
+	req, ok := h.bindVariantRequest(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productRepo.CreateVariant(c.Request.Context(), id, req, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao criar variante")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	variant, _ := findVariant(product, req.Options)
+
+	setETag(c, product)
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Variante criada com sucesso",
+		"data":    variant,
+	})
+}
+
+// UpdateProductVariant godoc
+// @Summary Substitui uma variante
+// @Description Substitui SKU, opções, preços e estoque da variante. Altera a versão (ETag) do produto.
+// @Tags variantes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param variant_id path int true "ID da variante"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Param variant body models.VariantRequest true "Dados da variante"
+// @Success 200 {object} models.ProductVariant
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/variants/{variant_id} [put]
+func (h *ProductHandler) UpdateProductVariant(c *gin.Context) {
+	id, variantID, ok := productAndVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar variante")
+		return
+	}
+
+	req, ok := h.bindVariantRequest(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productRepo.UpdateVariant(c.Request.Context(), id, variantID, req, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar variante")
+		return
+	}
+
+	h.present(product, h.options.BaseCurrency)
+	variant, err := variantByID(product, variantID)
+	if err != nil {
+		respondError(c, err, "Erro ao atualizar variante")
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Variante atualizada com sucesso",
+		"data":    variant,
+	})
+}
+
+// DeleteProductVariant godoc
+// @Summary Remove uma variante
+// @Description Remove a variante do produto. Altera a versão (ETag) do produto.
+// @Tags variantes
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param variant_id path int true "ID da variante"
+// @Param If-Match header string false "ETag do produto (obrigatório quando REQUIRE_IF_MATCH=true)"
+// @Success 200 {object} map[string]string
+// @Header 200 {string} ETag "Nova versão do produto"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 428 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Router /products/{id}/variants/{variant_id} [delete]
+func (h *ProductHandler) DeleteProductVariant(c *gin.Context) {
+	id, variantID, ok := productAndVariantIDs(c)
+	if !ok {
+		return
+	}
+
+	expectedVersion, err := h.expectedVersion(c)
+	if err != nil {
+		respondError(c, err, "Erro ao remover variante")
+		return
+	}
+
+	product, err := h.productRepo.DeleteVariant(c.Request.Context(), id, variantID, expectedVersion)
+	if err != nil {
+		respondError(c, err, "Erro ao remover variante")
+		return
+	}
+
+	setETag(c, product)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Variante removida com sucesso",
+	})
+}
@@ -0,0 +1,105 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func createVariant(t *testing.T, router *gin.Engine, body string) models.ProductVariant {
+	t.Helper()
+	recorder := serve(router, http.MethodPost, "/api/v1/products/1/variants", body)
+	expectStatus(t, recorder, http.StatusCreated)
+	var created struct {
+		Data models.ProductVariant `json:"data"`
+	}
+	decodeResponse(t, recorder, &created)
+	return created.Data
+}
+
+func TestVariantOptionsAreUnique(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	blue := createVariant(t, router, `{"options": {"Cor": " Azul ", "Tamanho": "M"}, "stock_quantity": 5}`)
+	if want := map[string]string{"cor": "Azul", "tamanho": "M"}; !reflect.DeepEqual(blue.Options, want) {
+		t.Errorf("options = %v, want %v", blue.Options, want)
+	}
+	red := createVariant(t, router, `{"options": {"cor": "Vermelho", "tamanho": "M"}, "stock_quantity": 3}`)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"same options", `{"options": {"COR": "Azul", "tamanho": " M"}}`, http.StatusConflict},
+		{"repeated option", `{"options": {"Cor": "Azul", "cor": "Verde"}}`, http.StatusBadRequest},
+		{"fewer options", `{"options": {"cor": "Verde"}}`, http.StatusBadRequest},
+		{"other options", `{"options": {"cor": "Verde", "material": "Couro"}}`, http.StatusBadRequest},
+		{"empty value", `{"options": {"cor": " ", "tamanho": "G"}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		recorder := serve(router, http.MethodPost, "/api/v1/products/1/variants", tt.body)
+		if recorder.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, recorder.Code, tt.status, recorder.Body)
+		}
+	}
+
+	path := fmt.Sprintf("/api/v1/products/1/variants/%d", red.ID)
+	expectStatus(t, serve(router, http.MethodPut, path, `{"options": {"cor": "Azul", "tamanho": "M"}}`), http.StatusConflict)
+	// A variant keeps its own options when replaced.
+	expectStatus(t, serve(router, http.MethodPut, path, `{"options": {"cor": "Vermelho", "tamanho": "M"}, "stock_quantity": 4}`), http.StatusOK)
+}
+
+func TestVariantsKeepTheirOwnStock(t *testing.T) {
+	router := newTestRouter(t, handlers.ProductHandlerOptions{})
+
+	blue := createVariant(t, router, `{"options": {"cor": "Azul"}, "stock_quantity": 5}`)
+	red := createVariant(t, router, `{"options": {"cor": "Vermelho"}, "stock_quantity": 3}`)
+
+	expectProduct := func(totalStock, totalAvailable int, variantStock map[int][2]int) {
+		t.Helper()
+		recorder := serve(router, http.MethodGet, "/api/v1/products/1", "")
+		expectStatus(t, recorder, http.StatusOK)
+		var response struct {
+			Data models.Product `json:"data"`
+		}
+		decodeResponse(t, recorder, &response)
+		product := response.Data
+		if product.TotalStock != totalStock || product.TotalAvailable != totalAvailable {
+			t.Errorf("total_stock = %d, total_available = %d, want %d and %d",
+				product.TotalStock, product.TotalAvailable, totalStock, totalAvailable)
+		}
+		if len(product.Variants) != len(variantStock) {
+			t.Errorf("%d variants, want %d", len(product.Variants), len(variantStock))
+		}
+		for _, variant := range product.Variants {
+			want := variantStock[variant.ID]
+			if variant.StockQuantity != want[0] || variant.AvailableQuantity != want[1] {
+				t.Errorf("variant %d: stock = %d, available = %d, want %d and %d",
+					variant.ID, variant.StockQuantity, variant.AvailableQuantity, want[0], want[1])
+			}
+		}
+	}
+	expectProduct(8, 8, map[int][2]int{blue.ID: {5, 5}, red.ID: {3, 3}})
+
+	movement := fmt.Sprintf(`{"variant_id": %d, "quantity": -2, "reason": "sale"}`, blue.ID)
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/stock/movements", movement), http.StatusCreated)
+	expectProduct(6, 6, map[int][2]int{blue.ID: {3, 3}, red.ID: {3, 3}})
+
+	// Red has three units of its own, whatever blue has.
+	reservation := fmt.Sprintf(`{"variant_id": %d, "quantity": 4}`, red.ID)
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/reservations", reservation), http.StatusConflict)
+	reservation = fmt.Sprintf(`{"variant_id": %d, "quantity": 3}`, red.ID)
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/reservations", reservation), http.StatusCreated)
+	expectProduct(6, 3, map[int][2]int{blue.ID: {3, 3}, red.ID: {3, 0}})
+
+	expectStatus(t, serve(router, http.MethodPost, "/api/v1/products/1/reservations", `{"quantity": 1}`), http.StatusBadRequest)
+	expectStatus(t, serve(router, http.MethodDelete, fmt.Sprintf("/api/v1/products/1/variants/%d", red.ID), ""), http.StatusConflict)
+	expectStatus(t, serve(router, http.MethodDelete, fmt.Sprintf("/api/v1/products/1/variants/%d", blue.ID), ""), http.StatusOK)
+	expectProduct(3, 0, map[int][2]int{red.ID: {3, 0}})
+}
@@ -0,0 +1,39 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/repositories"
+)
+
+// StartReservationSweeper releases, every interval, the stock held by
+// reservations that expired without being confirmed or cancelled. It stops
+// when ctx is cancelled.
+func StartReservationSweeper(ctx context.Context, store repositories.ProductStore, interval time.Duration) {
+	if interval <= 0 {
+		log.Println("Liberação automática de reservas expiradas desativada")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			expired, err := store.ExpireReservations(ctx, time.Now())
+			if err != nil {
+				log.Printf("Erro ao liberar reservas expiradas: %v", err)
+			} else if expired > 0 {
+				log.Printf("%d reserva(s) expirada(s) liberada(s)", expired)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/repositories"
+)
+
+func TestReservationSweeperReleasesExpiredStock(t *testing.T) {
+	store := repositories.NewMemoryProductRepository()
+	if err := store.SeedInitialData(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	expired, err := store.ReserveStock(ctx, 1, models.ReservationRequest{Quantity: 5}, time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := store.ReserveStock(ctx, 1, models.ReservationRequest{Quantity: 3}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sweeperCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	StartReservationSweeper(sweeperCtx, store, 10*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		reservation, err := store.GetReservation(ctx, expired.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reservation.Status == models.ReservationExpired {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("status = %s, want expired", reservation.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	product, err := store.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.ReservedQuantity != active.Quantity {
+		t.Errorf("reserved = %d, want %d held by the active reservation", product.ReservedQuantity, active.Quantity)
+	}
+	if reservation, _ := store.GetReservation(ctx, active.ID); reservation.Status != models.ReservationActive {
+		t.Errorf("active reservation status = %s", reservation.Status)
+	}
+}
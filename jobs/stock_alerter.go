@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/notify"
+	"github.com/seuusuario/api-rest-go/repositories"
+)
+
+// StartStockAlerter checks, every interval, which products changed stock
+// status and sends an alert through notifier for those that reached their
+// reorder point or ran out. An alert that fails is retried on the next run.
+// It stops when ctx is cancelled.
+func StartStockAlerter(ctx context.Context, store repositories.ProductStore, notifier notify.Notifier, interval time.Duration) {
+	if notifier == nil || interval <= 0 {
+		log.Println("Alertas de estoque desativados")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			sendStockAlerts(ctx, store, notifier)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func sendStockAlerts(ctx context.Context, store repositories.ProductStore, notifier notify.Notifier) {
+	changes, err := store.StockStatusChanges(ctx)
+	if err != nil {
+		log.Printf("Erro ao verificar alertas de estoque: %v", err)
+		return
+	}
+
+	for _, change := range changes {
+		status := change.Product.StockStatus
+		// Improvements, such as a receipt restocking the product, are only
+		// recorded so the next drop alerts again.
+		if models.StockStatusWorse(change.Previous, status) {
+			err := notifier.Notify(ctx, notify.Alert{
+				Product:        models.NewLowStockItem(change.Product),
+				PreviousStatus: change.Previous,
+				OccurredAt:     time.Now(),
+			})
+			if err != nil {
+				log.Printf("Erro ao enviar alerta de estoque do produto %d: %v", change.Product.ID, err)
+				continue
+			}
+		}
+
+		if err := store.AcknowledgeStockStatus(ctx, change.Product.ID, status); err != nil {
+			log.Printf("Erro ao registrar alerta de estoque do produto %d: %v", change.Product.ID, err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+	"github.com/seuusuario/api-rest-go/notify"
+	"github.com/seuusuario/api-rest-go/repositories"
+)
+
+// recordingNotifier keeps the alerts it was given and fails while err is set.
+type recordingNotifier struct {
+	alerts []notify.Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert notify.Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestSendStockAlertsOncePerWorsening(t *testing.T) {
+	store := repositories.NewMemoryProductRepository()
+	if err := store.SeedInitialData(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	notifier := &recordingNotifier{}
+
+	product, err := store.Create(ctx, models.CreateProductRequest{
+		Name:          "Cabo USB-C",
+		SKU:           "CB-USBC",
+		Prices:        map[string]money.Money{"BRL": money.MustParse("29.90")},
+		StockQuantity: 10,
+		ReorderPoint:  5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	move := func(quantity int, reason string) {
+		t.Helper()
+		if _, _, err := store.RecordStockMovement(ctx, product.ID, models.StockMovementRequest{Quantity: quantity, Reason: reason}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectAlerts := func(step string, statuses ...string) {
+		t.Helper()
+		sendStockAlerts(ctx, store, notifier)
+		if len(notifier.alerts) != len(statuses) {
+			t.Fatalf("%s: %d alerts, want %d", step, len(notifier.alerts), len(statuses))
+		}
+		for i, status := range statuses {
+			alert := notifier.alerts[i]
+			if alert.Product.ProductID != product.ID || alert.Product.StockStatus != status {
+				t.Errorf("%s: alert %d is product %d %s, want product %d %s",
+					step, i, alert.Product.ProductID, alert.Product.StockStatus, product.ID, status)
+			}
+		}
+	}
+
+	expectAlerts("in stock")
+
+	move(-5, models.StockReasonSale)
+	expectAlerts("at the reorder point", models.StockStatusLow)
+	expectAlerts("unchanged", models.StockStatusLow)
+
+	move(-2, models.StockReasonSale)
+	expectAlerts("still low", models.StockStatusLow)
+
+	notifier.err = errors.New("smtp indisponível")
+	move(-3, models.StockReasonSale)
+	expectAlerts("notifier down", models.StockStatusLow)
+
+	notifier.err = nil
+	expectAlerts("retry", models.StockStatusLow, models.StockStatusOutOfStock)
+	if previous := notifier.alerts[1].PreviousStatus; previous != models.StockStatusLow {
+		t.Errorf("previous status = %s, want %s", previous, models.StockStatusLow)
+	}
+
+	move(20, models.StockReasonReceipt)
+	expectAlerts("restocked", models.StockStatusLow, models.StockStatusOutOfStock)
+
+	move(-16, models.StockReasonSale)
+	expectAlerts("low again", models.StockStatusLow, models.StockStatusOutOfStock, models.StockStatusLow)
+}
@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/repositories"
+	"github.com/seuusuario/api-rest-go/storage"
+)
+
+// StartTrashPurger permanently removes, every interval, the products that have
+// been in the trash for longer than retention, along with their image files.
+// It stops when ctx is cancelled.
+func StartTrashPurger(ctx context.Context, store repositories.ProductStore, images storage.Storage, retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		log.Println("Limpeza automática da lixeira desativada")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := store.PurgeDeleted(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Erro ao limpar lixeira: %v", err)
+			} else if len(purged) > 0 {
+				log.Printf("%d produto(s) removido(s) definitivamente da lixeira", len(purged))
+			}
+
+			// The records are already gone, so a file that fails to be removed
+			// is only logged.
+			for _, product := range purged {
+				for _, image := range product.Images {
+					if err := images.Delete(ctx, image.Key); err != nil {
+						log.Printf("Erro ao remover imagem %s do produto %d: %v", image.Key, product.ID, err)
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
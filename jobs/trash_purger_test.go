@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/repositories"
+	"github.com/seuusuario/api-rest-go/storage"
+)
+
+func TestTrashPurgerRemovesOldProductsAndImages(t *testing.T) {
+	store := repositories.NewMemoryProductRepository()
+	if err := store.SeedInitialData(); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	images, err := storage.NewLocal(dir, "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	const key = "products/1/foto.png"
+	if err := images.Save(ctx, key, strings.NewReader("png")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddImage(ctx, 1, models.ProductImage{Key: key, ContentType: "image/png", Size: 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	const retention = 200 * time.Millisecond
+	if err := store.Delete(ctx, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(retention + 50*time.Millisecond)
+	if err := store.Delete(ctx, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first run happens right away; the interval keeps a second one from
+	// reaching product 2.
+	purgerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	StartTrashPurger(purgerCtx, store, images, retention, time.Hour)
+
+	deadline := time.Now().Add(time.Second)
+	var deleted []models.Product
+	for {
+		deleted, err = store.GetDeleted(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deleted) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("trash = %+v, want product 1 purged", deleted)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if deleted[0].ID != 2 {
+		t.Errorf("trash = %+v, want only product 2", deleted)
+	}
+	if _, err := store.Restore(ctx, 1, nil); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Restore error = %v, want not found", err)
+	}
+
+	// The files are removed after the purge returns.
+	for {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(key)))
+		if os.IsNotExist(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("image file still exists: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
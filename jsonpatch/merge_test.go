@@ -0,0 +1,54 @@
+package jsonpatch
+
+import "testing"
+
+// The examples from RFC 7396, Appendix A.
+func TestApplyMergePatchRFC7396(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{`{"a": "b"}`, `{"a": null}`, `{}`},
+		{`{"a": "b", "b": "c"}`, `{"a": null}`, `{"b": "c"}`},
+		{`{"a": ["b"]}`, `{"a": "c"}`, `{"a": "c"}`},
+		{`{"a": "c"}`, `{"a": ["b"]}`, `{"a": ["b"]}`},
+		{`{"a": {"b": "c"}}`, `{"a": {"b": "d", "c": null}}`, `{"a": {"b": "d"}}`},
+		{`{"a": [{"b": "c"}]}`, `{"a": [1]}`, `{"a": [1]}`},
+		{`["a", "b"]`, `["c", "d"]`, `["c", "d"]`},
+		{`{"a": "b"}`, `["c"]`, `["c"]`},
+		{`{"a": "foo"}`, `null`, `null`},
+		{`{"a": "foo"}`, `"bar"`, `"bar"`},
+		{`{"e": null}`, `{"a": 1}`, `{"e": null, "a": 1}`},
+		{`[1, 2]`, `{"a": "b", "c": null}`, `{"a": "b"}`},
+		{`{}`, `{"a": {"bb": {"ccc": null}}}`, `{"a": {"bb": {}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" + "+tt.patch, func(t *testing.T) {
+			got, err := ApplyMergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyMergePatch error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyMergePatchKeepsNumbers(t *testing.T) {
+	got, err := ApplyMergePatch([]byte(`{"stock": 12345678901234567890}`), []byte(`{"name": "x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, got, `{"stock": 12345678901234567890, "name": "x"}`)
+}
+
+func TestApplyMergePatchInvalid(t *testing.T) {
+	for _, patch := range []string{`{"a": }`, `{"a": 1} {"b": 2}`} {
+		if _, err := ApplyMergePatch([]byte(`{}`), []byte(patch)); err == nil {
+			t.Errorf("ApplyMergePatch(%s) succeeded, want error", patch)
+		}
+	}
+}
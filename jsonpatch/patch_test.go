@@ -0,0 +1,242 @@
+package jsonpatch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	gotValue, err := decode(got)
+	if err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	wantValue, err := decode([]byte(want))
+	if err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !equal(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// The examples from RFC 6902, Appendix A.
+func TestApplyPatchRFC6902(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name:  "A.8 testing a value: success",
+			doc:   `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			want:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:  "A.14 ~ escape ordering",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": 10}]`,
+			want:  `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		{
+			name:  "appending with the - index",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": 3}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:  "test with numerically equal numbers",
+			doc:   `{"price": 1}`,
+			patch: `[{"op": "test", "path": "/price", "value": 1.0}, {"op": "test", "path": "/price", "value": 1e0}]`,
+			want:  `{"price": 1}`,
+		},
+		{
+			name:  "copy",
+			doc:   `{"a": {"b": [1]}}`,
+			patch: `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b/-", "value": 2}]`,
+			want:  `{"a": {"b": [1]}, "c": {"b": [1, 2]}}`,
+		},
+		{
+			name:  "move to a sibling sharing the prefix",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/ab"}]`,
+			want:  `{"ab": 1}`,
+		},
+		{
+			name:  "replacing the whole document",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "replace", "path": "", "value": [1]}]`,
+			want:  `[1]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ApplyPatch error = %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyPatchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		patch      string
+		testFailed bool
+	}{
+		{
+			name:       "A.9 testing a value: error",
+			doc:        `{"baz": "qux"}`,
+			patch:      `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			testFailed: true,
+		},
+		{
+			name:  "A.12 adding to a nonexistent target",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+		},
+		{
+			name:  "A.13 invalid JSON Patch document",
+			doc:   `{"foo": "bar", "baz": 1}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+		},
+		{
+			name:       "A.15 comparing strings and numbers",
+			doc:        `{"/": 9, "~1": 10}`,
+			patch:      `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			testFailed: true,
+		},
+		{
+			name:  "moving a value into its own child",
+			doc:   `{"a": {"b": {}}}`,
+			patch: `[{"op": "move", "from": "/a", "path": "/a/b/c"}]`,
+		},
+		{
+			name:  "the - index outside add",
+			doc:   `{"foo": [1]}`,
+			patch: `[{"op": "remove", "path": "/foo/-"}]`,
+		},
+		{
+			name:  "index past the end",
+			doc:   `{"foo": [1]}`,
+			patch: `[{"op": "add", "path": "/foo/2", "value": 2}]`,
+		},
+		{
+			name:  "index with a leading zero",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "replace", "path": "/foo/01", "value": 3}]`,
+		},
+		{
+			name:  "replacing a missing member",
+			doc:   `{}`,
+			patch: `[{"op": "replace", "path": "/a", "value": 1}]`,
+		},
+		{
+			name:  "missing value",
+			doc:   `{}`,
+			patch: `[{"op": "add", "path": "/a"}]`,
+		},
+		{
+			name:  "unknown operation",
+			doc:   `{}`,
+			patch: `[{"op": "merge", "path": "/a", "value": 1}]`,
+		},
+		{
+			name:  "pointer without a leading slash",
+			doc:   `{"a": 1}`,
+			patch: `[{"op": "remove", "path": "a"}]`,
+		},
+		{
+			name:       "a failing operation discards the earlier ones",
+			doc:        `{"a": 1}`,
+			patch:      `[{"op": "replace", "path": "/a", "value": 2}, {"op": "test", "path": "/a", "value": 1}]`,
+			testFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatalf("ApplyPatch = %s, want error", got)
+			}
+			if tt.testFailed && !errors.Is(err, ErrTestFailed) {
+				t.Errorf("error = %v, want ErrTestFailed", err)
+			}
+		})
+	}
+}
+
+func TestApplyPatchMoveIntoOwnChild(t *testing.T) {
+	_, err := ApplyPatch([]byte(`{"a": {"b": {}}}`), []byte(`[{"op": "move", "from": "/a", "path": "/a/b"}]`))
+	if err == nil || !strings.Contains(err.Error(), "dentro de si mesmo") {
+		t.Errorf("error = %v, want the move into its own child to be rejected", err)
+	}
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/config"
+	"github.com/seuusuario/api-rest-go/cursor"
 	"github.com/seuusuario/api-rest-go/database"
 	"github.com/seuusuario/api-rest-go/handlers"
+	"github.com/seuusuario/api-rest-go/jobs"
+	"github.com/seuusuario/api-rest-go/money"
+	"github.com/seuusuario/api-rest-go/notify"
 	"github.com/seuusuario/api-rest-go/repositories"
 	"github.com/seuusuario/api-rest-go/routes"
+	"github.com/seuusuario/api-rest-go/storage"
 )
 
 // @title Products Backend API Golang
@@ -18,8 +28,108 @@ import (
 // @host products-backend-production-a43e.up.railway.app
 // @schemes https
 func main() {
-	database.Connect()
-	defer database.DB.Close()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal("Erro ao executar migrações: ", err)
+		}
+		return
+	}
+
+	cfg := config.Load()
+
+	moneyFormat, err := money.ParseJSONFormat(cfg.MoneyJSONFormat)
+	if err != nil {
+		log.Fatal("Configuração inválida: ", err)
+	}
+	money.SetJSONFormat(moneyFormat)
+	if !money.SupportsCurrency(cfg.BaseCurrency) {
+		log.Fatalf("Configuração inválida: BASE_CURRENCY %s não tem duas casas decimais", cfg.BaseCurrency)
+	}
+
+	var productRepo repositories.ProductStore
+	var categoryRepo repositories.CategoryStore
+	switch cfg.StorageBackend {
+	case "memory":
+		memoryRepo := repositories.NewMemoryProductRepository()
+		if err := memoryRepo.SeedInitialData(); err != nil {
+			log.Fatal("Erro ao inserir dados iniciais:", err)
+		}
+		productRepo = memoryRepo
+		categoryRepo = repositories.NewMemoryCategoryRepository(memoryRepo)
+		log.Println("Usando armazenamento em memória")
+	case "postgres":
+		if err := database.Connect(); err != nil {
+			log.Fatal("Erro ao conectar ao banco de dados: ", err)
+		}
+		defer database.DB.Close()
+
+		if cfg.AutoMigrate {
+			migrator, err := database.NewMigrator(database.DB)
+			if err != nil {
+				log.Fatal("Erro ao carregar migrações: ", err)
+			}
+			if err := migrator.Up(context.Background()); err != nil {
+				log.Fatal("Erro ao aplicar migrações: ", err)
+			}
+		}
+
+		timeouts := repositories.QueryTimeouts{
+			Read:    cfg.DBReadTimeout,
+			Write:   cfg.DBWriteTimeout,
+			Filter:  cfg.DBFilterTimeout,
+			Suggest: cfg.DBSuggestTimeout,
+		}
+		productRepo = repositories.NewProductRepository(database.DB, timeouts)
+		categoryRepo = repositories.NewCategoryRepository(database.DB, timeouts)
+	default:
+		log.Fatalf("Armazenamento desconhecido: %q (use postgres ou memory)", cfg.StorageBackend)
+	}
+
+	var imageStorage storage.Storage
+	switch cfg.ImageStorage {
+	case "local":
+		local, err := storage.NewLocal(cfg.ImageStorageDir, cfg.ImageBaseURL)
+		if err != nil {
+			log.Fatal("Erro ao preparar armazenamento de imagens: ", err)
+		}
+		imageStorage = local
+	default:
+		log.Fatalf("Armazenamento de imagens desconhecido: %q", cfg.ImageStorage)
+	}
+
+	var stockNotifier notify.Notifier
+	switch cfg.StockAlertNotifier {
+	case "log":
+		stockNotifier = notify.NewLog()
+	case "webhook":
+		webhook, err := notify.NewWebhook(cfg.StockAlertWebhookURL, 10*time.Second)
+		if err != nil {
+			log.Fatal("Erro ao preparar alertas de estoque: ", err)
+		}
+		stockNotifier = webhook
+	case "smtp":
+		var to []string
+		for _, address := range strings.Split(cfg.StockAlertSMTPTo, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				to = append(to, address)
+			}
+		}
+		mailer, err := notify.NewSMTP(cfg.StockAlertSMTPAddr, cfg.StockAlertSMTPFrom, to)
+		if err != nil {
+			log.Fatal("Erro ao preparar alertas de estoque: ", err)
+		}
+		stockNotifier = mailer
+	case "none":
+	default:
+		log.Fatalf("Notificador de alertas de estoque desconhecido: %q", cfg.StockAlertNotifier)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs.StartTrashPurger(ctx, productRepo, imageStorage, cfg.TrashRetention, cfg.TrashPurgeInterval)
+	jobs.StartReservationSweeper(ctx, productRepo, cfg.ReservationSweepInterval)
+	jobs.StartStockAlerter(ctx, productRepo, stockNotifier, cfg.StockAlertInterval)
 
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -30,17 +140,44 @@ func main() {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
-	productRepo := repositories.NewProductRepository(database.DB)
-
-	productHandler := handlers.NewProductHandler(productRepo)
+	// Local images are served by the API itself unless IMAGE_BASE_URL points elsewhere.
+	if cfg.ImageStorage == "local" && strings.HasPrefix(cfg.ImageBaseURL, "/") {
+		router.Static(cfg.ImageBaseURL, cfg.ImageStorageDir)
+	}
 
-	routes.SetupRoutes(router, productHandler)
+	// Without a configured secret, tokens only stay valid until the restart and
+	// are rejected by other instances.
+	cursorKey := []byte(cfg.CursorSecret)
+	if len(cursorKey) == 0 {
+		log.Println("CURSOR_SECRET não definido; usando uma chave aleatória para os tokens de paginação")
+		cursorKey = make([]byte, 32)
+		if _, err := rand.Read(cursorKey); err != nil {
+			log.Fatal("Erro ao gerar chave dos tokens de paginação: ", err)
+		}
+	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	priceBuckets, err := handlers.ParsePriceBuckets(cfg.FacetPriceBuckets)
+	if err != nil {
+		log.Fatal("FACET_PRICE_BUCKETS inválido: ", err)
 	}
 
+	productHandler := handlers.NewProductHandler(productRepo, categoryRepo, imageStorage, handlers.ProductHandlerOptions{
+		RequireIfMatch: cfg.RequireIfMatch,
+		BaseCurrency:   cfg.BaseCurrency,
+		MaxImageBytes:  cfg.ImageMaxBytes,
+		PriceBuckets:   priceBuckets,
+		Cursors:        cursor.NewSigner(cursorKey),
+
+		ReservationTTL:    cfg.ReservationTTL,
+		ReservationMaxTTL: cfg.ReservationMaxTTL,
+	})
+
+	categoryHandler := handlers.NewCategoryHandler(categoryRepo)
+
+	routes.SetupRoutes(router, productHandler, categoryHandler, cfg.AuditActorHeader)
+
+	port := cfg.Port
+
 	log.Printf("Servidor iniciando na porta %s", port)
 	log.Printf("Acesse http://localhost:%s/health para verificar o status", port)
 	log.Printf("API disponível em http://localhost:%s/api/v1/products", port)
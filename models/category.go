@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+type Category struct {
+	ID        int       `json:"id" db:"id"`
+	Slug      string    `json:"slug" db:"slug"`
+	Name      string    `json:"name" db:"name"`
+	ParentID  *int      `json:"parent_id" db:"parent_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CategorySummary is the compact form embedded in products and breadcrumbs.
+type CategorySummary struct {
+	ID   int    `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// CategoryRequest is used for both creation and full replacement. An empty
+// Slug is derived from Name.
+type CategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Slug     string `json:"slug" binding:"omitempty,max=100,slug"`
+	ParentID *int   `json:"parent_id"`
+}
+
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
+}
+
+type CategoryDetail struct {
+	Category
+	// Breadcrumbs lists the ancestors from the root down to the category itself.
+	Breadcrumbs []CategorySummary `json:"breadcrumbs"`
+	Children    []Category        `json:"children"`
+}
+
+func (c Category) Summary() CategorySummary {
+	return CategorySummary{ID: c.ID, Slug: c.Slug, Name: c.Name}
+}
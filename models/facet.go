@@ -0,0 +1,82 @@
+package models
+
+import "github.com/seuusuario/api-rest-go/money"
+
+// Facets accepted by the facets query parameter of /products/filter.
+const (
+	FacetCategory = "category"
+	FacetPrice    = "price"
+	FacetStock    = "stock"
+)
+
+// FacetRequest selects the facets to count. PriceBuckets are increasing
+// boundaries: n boundaries make n+1 ranges, the first without a minimum and
+// the last without a maximum.
+type FacetRequest struct {
+	Category     bool
+	Price        bool
+	Stock        bool
+	PriceBuckets []money.Money
+}
+
+func (f FacetRequest) Any() bool {
+	return f.Category || f.Price || f.Stock
+}
+
+type CategoryFacet struct {
+	CategorySummary
+	Count int `json:"count" example:"12"`
+}
+
+// PriceRangeFacet counts the products priced from Min (inclusive) up to Max (exclusive).
+type PriceRangeFacet struct {
+	Min   *money.Money `json:"min" swaggertype:"string" example:"100.00"`
+	Max   *money.Money `json:"max" swaggertype:"string" example:"500.00"`
+	Count int          `json:"count" example:"4"`
+}
+
+type StockStatusFacet struct {
+	Status string `json:"status" example:"in_stock" enums:"in_stock,low,out_of_stock"`
+	Count  int    `json:"count" example:"9"`
+}
+
+// ProductFacets holds the requested facets. Each one is counted with the
+// current filter minus its own dimension, so selecting a category does not
+// hide the other categories.
+type ProductFacets struct {
+	Categories  []CategoryFacet    `json:"categories,omitempty"`
+	PriceRanges []PriceRangeFacet  `json:"price_ranges,omitempty"`
+	StockStatus []StockStatusFacet `json:"stock_status,omitempty"`
+}
+
+// WithoutCategory, WithoutPrice and WithoutStock drop the dimension of each facet.
+func (f ProductFilter) WithoutCategory() ProductFilter {
+	f.Category = ""
+	f.IncludeDescendants = false
+	f.CategoryIDs = nil
+	return f
+}
+
+func (f ProductFilter) WithoutPrice() ProductFilter {
+	f.MinPrice = nil
+	f.MaxPrice = nil
+	return f
+}
+
+func (f ProductFilter) WithoutStock() ProductFilter {
+	f.MinStock = nil
+	f.MaxStock = nil
+	f.StockStatus = ""
+	return f
+}
+
+// PriceRanges returns an empty range for each bucket defined by boundaries.
+func PriceRanges(boundaries []money.Money) []PriceRangeFacet {
+	ranges := make([]PriceRangeFacet, len(boundaries)+1)
+	for i := range boundaries {
+		boundary := boundaries[i]
+		ranges[i].Max = &boundary
+		ranges[i+1].Min = &boundary
+	}
+	return ranges
+}
@@ -0,0 +1,18 @@
+package models
+
+// ProductImage describes an uploaded image. Key locates the file in the image
+// storage; URL is derived from it when the product is sent to a client.
+type ProductImage struct {
+	ID          int    `json:"id"`
+	Key         string `json:"-"`
+	URL         string `json:"url,omitempty" example:"/uploads/products/1/3f2a9c.png"`
+	ContentType string `json:"content_type" example:"image/png"`
+	Size        int64  `json:"size"`
+	Position    int    `json:"position"`
+	Primary     bool   `json:"primary"`
+}
+
+// ImageOrderRequest lists every image ID of a product in the new order.
+type ImageOrderRequest struct {
+	ImageIDs []int `json:"image_ids" binding:"required,min=1"`
+}
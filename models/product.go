@@ -2,48 +2,205 @@ package models
 
 import (
 	"time"
+
+	"github.com/seuusuario/api-rest-go/money"
 )
 
 type Product struct {
-	ID            int       `json:"id" db:"id"`
-	Name          string    `json:"name" db:"name" binding:"required"`
-	Description   string    `json:"description" db:"description"`
-	Price         float64   `json:"price" db:"price" binding:"required"`
-	Category      string    `json:"category" db:"category"`
-	StockQuantity int       `json:"stock_quantity" db:"stock_quantity"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name" binding:"required"`
+	Description string `json:"description" db:"description"`
+	SKU         string `json:"sku,omitempty" db:"sku" example:"SM-S23-256-PT"`
+	GTIN        string `json:"gtin,omitempty" db:"gtin" example:"7891234567895"`
+	// Price and Currency hold the price in the currency selected by the request;
+	// Prices has the price in every currency the product is sold in.
+	Price         *money.Money           `json:"price,omitempty" swaggertype:"string" example:"2999.99"`
+	Currency      string                 `json:"currency,omitempty" example:"BRL"`
+	Prices        map[string]money.Money `json:"prices" db:"prices" swaggertype:"object,string"`
+	CategoryID    *int                   `json:"category_id,omitempty" db:"category_id"`
+	Category      *CategorySummary       `json:"category,omitempty"`
+	Tags          []string               `json:"tags" db:"tags"`
+	Options       []ProductOption        `json:"options,omitempty"`
+	Variants      []ProductVariant       `json:"variants,omitempty"`
+	Images        []ProductImage         `json:"images"`
+	StockQuantity int                    `json:"stock_quantity" db:"stock_quantity"`
+	TotalStock    int                    `json:"total_stock"`
+	// ReservedQuantity is held by active reservations. TotalReserved and
+	// TotalAvailable are the counterparts of TotalStock.
+	ReservedQuantity int `json:"reserved_quantity" db:"reserved_quantity"`
+	TotalReserved    int `json:"total_reserved"`
+	TotalAvailable   int `json:"total_available"`
+	// ReorderPoint is the available quantity at or below which the product is
+	// low on stock, and ReorderQuantity how much to order then. Zero means unset.
+	ReorderPoint    int        `json:"reorder_point,omitempty" db:"reorder_point" example:"10"`
+	ReorderQuantity int        `json:"reorder_quantity,omitempty" db:"reorder_quantity" example:"50"`
+	StockStatus     string     `json:"stock_status" example:"in_stock" enums:"in_stock,low,out_of_stock"`
+	Version         int        `json:"version" db:"version"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// CreateProductRequest takes the price in the base currency in Price and the
+// prices in other currencies in Prices. Handlers fold Price into Prices before
+// the request reaches a store, which only reads Prices.
 type CreateProductRequest struct {
-	Name          string  `json:"name" binding:"required"`
-	Description   string  `json:"description"`
-	Price         float64 `json:"price" binding:"required"`
-	Category      string  `json:"category"`
-	StockQuantity int     `json:"stock_quantity"`
+	Name            string                 `json:"name" binding:"required"`
+	Description     string                 `json:"description"`
+	SKU             string                 `json:"sku" binding:"omitempty,sku" example:"SM-S23-256-PT"`
+	GTIN            string                 `json:"gtin" binding:"omitempty,gtin" example:"7891234567895"`
+	Price           money.Money            `json:"price" binding:"omitempty,gt=0" swaggertype:"string" example:"2999.99"`
+	Prices          map[string]money.Money `json:"prices" binding:"omitempty,dive,keys,currency,endkeys,gt=0" swaggertype:"object,string"`
+	CategoryID      *int                   `json:"category_id"`
+	StockQuantity   int                    `json:"stock_quantity"`
+	ReorderPoint    int                    `json:"reorder_point" binding:"gte=0" example:"10"`
+	ReorderQuantity int                    `json:"reorder_quantity" binding:"gte=0" example:"50"`
 }
 
+// UpdateProductRequest is a partial update: nil fields are left untouched.
+// A non-nil Prices replaces every price of the product, an empty SKU or GTIN
+// removes it, a zero CategoryID removes the product from its category and a
+// zero ReorderPoint or ReorderQuantity unsets it.
 type UpdateProductRequest struct {
-	Name          string  `json:"name"`
-	Description   string  `json:"description"`
-	Price         float64 `json:"price"`
-	Category      string  `json:"category"`
-	StockQuantity int     `json:"stock_quantity"`
+	Name            *string                `json:"name"`
+	Description     *string                `json:"description"`
+	SKU             *string                `json:"sku"`
+	GTIN            *string                `json:"gtin"`
+	Prices          map[string]money.Money `json:"prices" swaggertype:"object,string"`
+	CategoryID      *int                   `json:"category_id"`
+	StockQuantity   *int                   `json:"stock_quantity"`
+	ReorderPoint    *int                   `json:"reorder_point"`
+	ReorderQuantity *int                   `json:"reorder_quantity"`
+}
+
+// ReplaceProductRequest is the full representation accepted by PUT and the
+// document that PATCH operations are applied to.
+// Price and Prices follow the same rules as in CreateProductRequest.
+type ReplaceProductRequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	Description     string                 `json:"description"`
+	SKU             string                 `json:"sku" binding:"omitempty,sku" example:"SM-S23-256-PT"`
+	GTIN            string                 `json:"gtin" binding:"omitempty,gtin" example:"7891234567895"`
+	Price           money.Money            `json:"price" binding:"omitempty,gt=0" swaggertype:"string" example:"2999.99"`
+	Prices          map[string]money.Money `json:"prices" binding:"omitempty,dive,keys,currency,endkeys,gt=0" swaggertype:"object,string"`
+	CategoryID      *int                   `json:"category_id"`
+	StockQuantity   int                    `json:"stock_quantity" binding:"gte=0"`
+	ReorderPoint    int                    `json:"reorder_point" binding:"gte=0" example:"10"`
+	ReorderQuantity int                    `json:"reorder_quantity" binding:"gte=0" example:"50"`
+}
+
+// ProductTagsRequest lists tags to add to a product. Tags are normalized to
+// lowercase slugs, so "Black Friday" is stored as "black-friday".
+type ProductTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,required,max=50" example:"promo,black-friday"`
+}
+
+// NewReplaceProductRequest builds the editable representation of product, with
+// the base currency price in Price and the remaining prices in Prices.
+func NewReplaceProductRequest(product Product, baseCurrency string) ReplaceProductRequest {
+	req := ReplaceProductRequest{
+		Name:            product.Name,
+		Description:     product.Description,
+		SKU:             product.SKU,
+		GTIN:            product.GTIN,
+		Prices:          make(map[string]money.Money),
+		CategoryID:      product.CategoryID,
+		StockQuantity:   product.StockQuantity,
+		ReorderPoint:    product.ReorderPoint,
+		ReorderQuantity: product.ReorderQuantity,
+	}
+	for currency, amount := range product.Prices {
+		if currency == baseCurrency {
+			req.Price = amount
+		} else {
+			req.Prices[currency] = amount
+		}
+	}
+	return req
 }
 
+func (r ReplaceProductRequest) ToUpdate() UpdateProductRequest {
+	categoryID := new(int)
+	if r.CategoryID != nil {
+		*categoryID = *r.CategoryID
+	}
+
+	return UpdateProductRequest{
+		Name:            &r.Name,
+		Description:     &r.Description,
+		SKU:             &r.SKU,
+		GTIN:            &r.GTIN,
+		Prices:          r.Prices,
+		CategoryID:      categoryID,
+		StockQuantity:   &r.StockQuantity,
+		ReorderPoint:    &r.ReorderPoint,
+		ReorderQuantity: &r.ReorderQuantity,
+	}
+}
+
+// ProductFilter.Category accepts a category slug or ID. CategoryIDs is filled
+// by the handler with the matching category and, when IncludeDescendants is
+// set, its subcategories. Options, read from options[name]=value query
+// parameters, matches products with a variant having all of those options.
+// MinStock and MaxStock apply to the total stock and StockStatus to the
+// computed stock status.
 type ProductFilter struct {
-	Name     string   `json:"name" form:"name"`
-	Category string   `json:"category" form:"category"`
-	MinPrice *float64 `json:"min_price" form:"min_price"`
-	MaxPrice *float64 `json:"max_price" form:"max_price"`
-	MinStock *int     `json:"min_stock" form:"min_stock"`
-	MaxStock *int     `json:"max_stock" form:"max_stock"`
+	Name               string            `json:"name" form:"name"`
+	Category           string            `json:"category" form:"category"`
+	IncludeDescendants bool              `json:"include_descendants" form:"include_descendants"`
+	CategoryIDs        []int             `json:"-" form:"-"`
+	Currency           string            `json:"currency" form:"currency"`
+	MinPrice           *money.Money      `json:"min_price" form:"-" swaggertype:"string"`
+	MaxPrice           *money.Money      `json:"max_price" form:"-" swaggertype:"string"`
+	MinStock           *int              `json:"min_stock" form:"min_stock"`
+	MaxStock           *int              `json:"max_stock" form:"max_stock"`
+	StockStatus        string            `json:"stock_status" form:"stock_status" binding:"omitempty,oneof=in_stock low out_of_stock"`
+	TagsAny            []string          `json:"tags_any" form:"tags_any"`
+	TagsAll            []string          `json:"tags_all" form:"tags_all"`
+	Options            map[string]string `json:"options" form:"-"`
 }
 
+// NextTokenRequest.Sort lists the sort fields as field:direction pairs and
+// After holds the values of those fields in the last row, in the same order.
+// Without Sort the results are ordered by id in Order, resuming after Row.
+// SortKeys is filled by the handler from Sort and After.
 type NextTokenRequest struct {
-	Row   int    `json:"row" form:"row"`
-	Order string `json:"order" form:"order"`
-	Limit int    `json:"limit" form:"limit"`
+	Row      int       `json:"row" form:"row"`
+	Order    string    `json:"order" form:"order"`
+	Limit    int       `json:"limit" form:"limit"`
+	Sort     string    `json:"sort,omitempty" form:"sort"`
+	After    []string  `json:"after,omitempty" form:"after"`
+	SortKeys []SortKey `json:"-" form:"-"`
+	// Offset skips rows in the page-number mode, where there is no cursor.
+	Offset int `json:"-" form:"-"`
+}
+
+// CountMode tells how FindByFilter computes the total of the filtered set.
+type CountMode string
+
+const (
+	CountExact     CountMode = "exact"
+	CountEstimated CountMode = "estimated"
+	CountNone      CountMode = "none"
+)
+
+// Total is the size of the filtered set, regardless of the page. Estimated is
+// set when it comes from the planner statistics instead of COUNT(*).
+type Total struct {
+	Count     int
+	Estimated bool
+}
+
+// PageRequest selects the page-number mode instead of the cursor.
+type PageRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type PageInfo struct {
+	Page       int `json:"page" example:"7"`
+	PageSize   int `json:"page_size" example:"10"`
+	TotalPages int `json:"total_pages" example:"40"`
 }
 
 type ProductFilterRequest struct {
@@ -51,9 +208,49 @@ type ProductFilterRequest struct {
 	NextToken NextTokenRequest `json:"next_token"`
 }
 
+// ProductFilterResponse.NextToken is an opaque signed token to be sent back
+// as the next_token query parameter to fetch the following page. Total is
+// omitted with include_total=false, and TotalEstimated tells it is an
+// approximation from the planner statistics. Pagination is only set in the
+// page-number mode and Category by the category listing.
 type ProductFilterResponse struct {
-	Data      []Product         `json:"data"`
-	Total     int               `json:"total"`
-	NextToken *NextTokenRequest `json:"next_token,omitempty"`
-	HasMore   bool              `json:"has_more"`
+	Data           []Product        `json:"data"`
+	Total          *int             `json:"total,omitempty" example:"150"`
+	TotalEstimated bool             `json:"total_estimated,omitempty"`
+	NextToken      string           `json:"next_token,omitempty" example:"eyJoIjoiOWYxYzJhIiwicyI6ImlkOmRlc2MiLCJhIjpbIjUiXSwibCI6MTB9.q5nB0fXz"`
+	HasMore        bool             `json:"has_more"`
+	Facets         *ProductFacets   `json:"facets,omitempty"`
+	Pagination     *PageInfo        `json:"pagination,omitempty"`
+	Category       *CategorySummary `json:"category,omitempty"`
+}
+
+const (
+	HistoryActionCreate  = "create"
+	HistoryActionUpdate  = "update"
+	HistoryActionDelete  = "delete"
+	HistoryActionRestore = "restore"
+	HistoryActionPurge   = "purge"
+)
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type ProductHistory struct {
+	ID        int64                  `json:"id" db:"id"`
+	ProductID int                    `json:"product_id" db:"product_id"`
+	Action    string                 `json:"action" db:"action"`
+	Actor     string                 `json:"actor" db:"actor"`
+	RequestID string                 `json:"request_id,omitempty" db:"request_id"`
+	Before    *Product               `json:"before,omitempty" db:"before"`
+	After     *Product               `json:"after,omitempty" db:"after"`
+	Changes   map[string]FieldChange `json:"changes" db:"changes"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+type ProductHistoryResponse struct {
+	Data      []ProductHistory `json:"data"`
+	NextToken string           `json:"next_token,omitempty"`
+	HasMore   bool             `json:"has_more"`
 }
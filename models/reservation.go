@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+const (
+	ReservationActive    = "active"
+	ReservationConfirmed = "confirmed"
+	ReservationCancelled = "cancelled"
+	ReservationExpired   = "expired"
+)
+
+// StockReservation holds stock of a product, or of one of its variants, while
+// a checkout completes. Active reservations count in the reserved quantity
+// until they are confirmed, cancelled or expire at ExpiresAt.
+type StockReservation struct {
+	ID        int64     `json:"id"`
+	ProductID int       `json:"product_id"`
+	VariantID *int      `json:"variant_id,omitempty"`
+	Quantity  int       `json:"quantity" example:"2"`
+	Status    string    `json:"status" example:"active"`
+	Reference string    `json:"reference,omitempty" example:"CHK-8812"`
+	Actor     string    `json:"actor"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReservationRequest.TTL is in seconds; without it the configured default applies.
+type ReservationRequest struct {
+	VariantID *int   `json:"variant_id"`
+	Quantity  int    `json:"quantity" binding:"required,min=1" example:"2"`
+	TTL       int    `json:"ttl_seconds" binding:"omitempty,min=1" example:"900"`
+	Reference string `json:"reference" binding:"max=100" example:"CHK-8812"`
+}
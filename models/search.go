@@ -0,0 +1,53 @@
+package models
+
+// ProductSearch is a full-text query. Query accepts the web search syntax:
+// quoted phrases, "or" between alternatives and "-" before excluded words.
+type ProductSearch struct {
+	Query    string `json:"q" form:"q" binding:"required,max=200"`
+	Currency string `json:"currency" form:"currency"`
+}
+
+// SearchNextToken resumes a search after the result with the given rank and ID.
+type SearchNextToken struct {
+	Rank  float32 `json:"rank" form:"rank"`
+	Row   int     `json:"row" form:"row"`
+	Limit int     `json:"limit" form:"limit"`
+}
+
+// ProductHighlights holds the matched words of each field wrapped in <mark> tags.
+type ProductHighlights struct {
+	Name        string `json:"name" example:"<mark>Notebook</mark> Dell XPS 13"`
+	Description string `json:"description" example:"Laptop ultrafino com processador Intel i7"`
+}
+
+type ProductSearchResult struct {
+	Product
+	Rank       float32           `json:"rank"`
+	Highlights ProductHighlights `json:"highlights"`
+}
+
+type ProductSearchResponse struct {
+	Data      []ProductSearchResult `json:"data"`
+	NextToken string                `json:"next_token,omitempty"`
+	HasMore   bool                  `json:"has_more"`
+}
+
+// SuggestRequest asks for the names that best complete or correct Prefix.
+type SuggestRequest struct {
+	Prefix string `json:"prefix" form:"prefix" binding:"required,max=100"`
+	Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// Suggestion is a product or category name similar to the typed text. Score
+// goes from 0 to 1 and Slug is only set for categories.
+type Suggestion struct {
+	Type  string  `json:"type" example:"product" enums:"product,category"`
+	ID    int     `json:"id" example:"1"`
+	Text  string  `json:"text" example:"Smartphone Samsung Galaxy S23"`
+	Slug  string  `json:"slug,omitempty" example:"smartphones"`
+	Score float32 `json:"score" example:"0.625"`
+}
+
+type SuggestResponse struct {
+	Data []Suggestion `json:"data"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// Fields accepted by the sort parameter of /products/filter. SortPrice uses
+// the price in the requested currency and SortStock the total stock.
+const (
+	SortID        = "id"
+	SortName      = "name"
+	SortPrice     = "price"
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortStock     = "stock"
+)
+
+var SortFields = []string{SortID, SortName, SortPrice, SortCreatedAt, SortUpdatedAt, SortStock}
+
+// SortKey is one field of a sort order. After, when set, holds the value of
+// the field in the last row of the previous page: an int for id and stock, a
+// string for name, money.Money for price and time.Time for the timestamps.
+type SortKey struct {
+	Field string
+	Desc  bool
+	After interface{}
+}
+
+func (k SortKey) String() string {
+	if k.Desc {
+		return k.Field + ":desc"
+	}
+	return k.Field + ":asc"
+}
+
+// Keys returns the sort order of the page. Without SortKeys it is the legacy
+// order by id, resuming after Row.
+func (t NextTokenRequest) Keys() []SortKey {
+	if len(t.SortKeys) > 0 {
+		return t.SortKeys
+	}
+	key := SortKey{Field: SortID, Desc: t.Order != "asc"}
+	if t.Row > 0 {
+		key.After = t.Row
+	}
+	return []SortKey{key}
+}
+
+// SortString formats keys like the sort parameter, e.g. "price:asc,id:desc".
+func SortString(keys []SortKey) string {
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = key.String()
+	}
+	return strings.Join(fields, ",")
+}
+
+// SortValue returns the value of field in product as it is sent in the cursor.
+// The price must already be resolved to the requested currency.
+func SortValue(product Product, field string) string {
+	switch field {
+	case SortName:
+		return product.Name
+	case SortPrice:
+		if product.Price == nil {
+			return ""
+		}
+		return product.Price.String()
+	case SortCreatedAt:
+		return product.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortUpdatedAt:
+		return product.UpdatedAt.UTC().Format(time.RFC3339Nano)
+	case SortStock:
+		return strconv.Itoa(product.TotalStock)
+	}
+	return strconv.Itoa(product.ID)
+}
+
+// ParseSortValue reads a cursor value of field into the type documented in SortKey.
+func ParseSortValue(field, value string) (interface{}, error) {
+	switch field {
+	case SortName:
+		return value, nil
+	case SortPrice:
+		return money.Parse(value)
+	case SortCreatedAt, SortUpdatedAt:
+		return time.Parse(time.RFC3339Nano, value)
+	case SortID, SortStock:
+		return strconv.Atoi(value)
+	}
+	return nil, fmt.Errorf("campo de ordenação desconhecido: %s", field)
+}
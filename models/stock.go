@@ -0,0 +1,160 @@
+package models
+
+import "time"
+
+// Stock statuses. A product is low on stock when what is available has reached
+// its reorder point, and out of stock when nothing is available.
+const (
+	StockStatusInStock    = "in_stock"
+	StockStatusLow        = "low"
+	StockStatusOutOfStock = "out_of_stock"
+)
+
+// StockStatusFor computes the stock status from the available quantity. A zero
+// reorder point means the product has none and is never low.
+func StockStatusFor(available, reorderPoint int) string {
+	switch {
+	case available <= 0:
+		return StockStatusOutOfStock
+	case available <= reorderPoint:
+		return StockStatusLow
+	}
+	return StockStatusInStock
+}
+
+// StockStatusWorse reports whether moving from previous to status is a step
+// towards running out, which is what stock alerts are sent for.
+func StockStatusWorse(previous, status string) bool {
+	severity := map[string]int{StockStatusInStock: 0, StockStatusLow: 1, StockStatusOutOfStock: 2}
+	return severity[status] > severity[previous]
+}
+
+// Stock movement reasons. Receipts and returns add stock, sales and losses
+// remove it and corrections go either way. Initial is only recorded by the
+// API itself, for the stock a product or variant is created with.
+const (
+	StockReasonInitial    = "initial"
+	StockReasonReceipt    = "receipt"
+	StockReasonSale       = "sale"
+	StockReasonReturn     = "return"
+	StockReasonLoss       = "loss"
+	StockReasonCorrection = "correction"
+)
+
+// StockMovement is an entry of the stock ledger. Quantity is the signed change
+// and Balance the stock of the product, or of the variant when VariantID is
+// set, right after the movement.
+type StockMovement struct {
+	ID        int64     `json:"id"`
+	ProductID int       `json:"product_id"`
+	VariantID *int      `json:"variant_id,omitempty"`
+	Quantity  int       `json:"quantity" example:"-2"`
+	Balance   int       `json:"balance" example:"48"`
+	Reason    string    `json:"reason" example:"sale"`
+	Reference string    `json:"reference,omitempty" example:"PED-10293"`
+	Actor     string    `json:"actor"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StockMovementRequest records a movement. Products with variants keep their
+// stock in the variants, so VariantID is required for them.
+type StockMovementRequest struct {
+	VariantID *int   `json:"variant_id"`
+	Quantity  int    `json:"quantity" binding:"required" example:"-2"`
+	Reason    string `json:"reason" binding:"required,oneof=receipt sale return loss correction" example:"sale"`
+	Reference string `json:"reference" binding:"max=100" example:"PED-10293"`
+}
+
+// StockMovementFilter narrows the movement listing of a product.
+type StockMovementFilter struct {
+	Reason    string `json:"reason,omitempty" form:"reason" binding:"omitempty,oneof=initial receipt sale return loss correction"`
+	VariantID *int   `json:"variant_id,omitempty" form:"variant_id"`
+}
+
+type StockMovementResponse struct {
+	Data      []StockMovement `json:"data"`
+	NextToken string          `json:"next_token,omitempty"`
+	HasMore   bool            `json:"has_more"`
+}
+
+// StockReconciliation compares the stock of a product, or of one of its
+// variants, with the sum of its ledger. Difference is StockQuantity minus
+// LedgerQuantity and is zero when both agree.
+type StockReconciliation struct {
+	ProductID      int  `json:"product_id"`
+	VariantID      *int `json:"variant_id,omitempty"`
+	StockQuantity  int  `json:"stock_quantity"`
+	LedgerQuantity int  `json:"ledger_quantity"`
+	Difference     int  `json:"difference"`
+}
+
+// ReconciliationFilter limits the check to one product when ProductID is set
+// and to the rows that disagree when DiscrepanciesOnly is set.
+type ReconciliationFilter struct {
+	ProductID         int
+	DiscrepanciesOnly bool
+}
+
+type StockReconciliationResponse struct {
+	Data       []StockReconciliation `json:"data"`
+	Consistent bool                  `json:"consistent"`
+}
+
+// StockDirectionValid reports whether the sign of quantity matches reason:
+// receipts and returns must be positive and sales and losses negative.
+func StockDirectionValid(reason string, quantity int) bool {
+	switch reason {
+	case StockReasonInitial, StockReasonReceipt, StockReasonReturn:
+		return quantity > 0
+	case StockReasonSale, StockReasonLoss:
+		return quantity < 0
+	default:
+		return quantity != 0
+	}
+}
+
+// LowStockItem is a row of the low stock report: a product whose available
+// stock reached its reorder point or ran out.
+type LowStockItem struct {
+	ProductID       int    `json:"product_id"`
+	Name            string `json:"name"`
+	SKU             string `json:"sku,omitempty"`
+	StockStatus     string `json:"stock_status" example:"low"`
+	TotalStock      int    `json:"total_stock"`
+	TotalReserved   int    `json:"total_reserved"`
+	TotalAvailable  int    `json:"total_available"`
+	ReorderPoint    int    `json:"reorder_point"`
+	ReorderQuantity int    `json:"reorder_quantity"`
+}
+
+func NewLowStockItem(product Product) LowStockItem {
+	return LowStockItem{
+		ProductID:       product.ID,
+		Name:            product.Name,
+		SKU:             product.SKU,
+		StockStatus:     product.StockStatus,
+		TotalStock:      product.TotalStock,
+		TotalReserved:   product.TotalReserved,
+		TotalAvailable:  product.TotalAvailable,
+		ReorderPoint:    product.ReorderPoint,
+		ReorderQuantity: product.ReorderQuantity,
+	}
+}
+
+type LowStockResponse struct {
+	Data  []LowStockItem `json:"data"`
+	Total int            `json:"total"`
+}
+
+// LowStockFilter.Status narrows the report to low or out of stock products.
+type LowStockFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=low out_of_stock"`
+}
+
+// StockStatusChange is a product whose stock status differs from the one
+// stock alerts last handled for it.
+type StockStatusChange struct {
+	Product  Product
+	Previous string
+}
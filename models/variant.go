@@ -0,0 +1,99 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// ProductVariant is a sellable version of a product, such as a color and size
+// combination. Prices holds the variant's own prices; in any other currency the
+// variant is sold at the product price. Price and Currency hold the price in
+// the currency selected by the request.
+type ProductVariant struct {
+	ID            int                    `json:"id"`
+	SKU           string                 `json:"sku,omitempty" example:"CAM-AZUL-M"`
+	Options       map[string]string      `json:"options" swaggertype:"object,string"`
+	Price         *money.Money           `json:"price,omitempty" swaggertype:"string" example:"89.90"`
+	Currency      string                 `json:"currency,omitempty" example:"BRL"`
+	Prices        map[string]money.Money `json:"prices,omitempty" swaggertype:"object,string"`
+	StockQuantity int                    `json:"stock_quantity"`
+	// ReservedQuantity is held by active reservations and AvailableQuantity is
+	// what is left to sell.
+	ReservedQuantity  int `json:"reserved_quantity"`
+	AvailableQuantity int `json:"available_quantity"`
+}
+
+// ProductOption is an option axis of a product, like "cor" or "tamanho", with
+// the values used by its variants.
+type ProductOption struct {
+	Name   string   `json:"name" example:"cor"`
+	Values []string `json:"values" example:"azul,preto"`
+}
+
+// VariantRequest creates or replaces a variant. Option names are normalized to
+// lowercase slugs and every variant of a product must use the same names.
+// Price is the variant price in the base currency, like in CreateProductRequest.
+type VariantRequest struct {
+	SKU           string                 `json:"sku" binding:"omitempty,sku" example:"CAM-AZUL-M"`
+	Options       map[string]string      `json:"options" binding:"required,min=1,max=5,dive,keys,required,max=50,endkeys,required,max=50" swaggertype:"object,string"`
+	Price         money.Money            `json:"price" binding:"omitempty,gt=0" swaggertype:"string" example:"89.90"`
+	Prices        map[string]money.Money `json:"prices" binding:"omitempty,dive,keys,currency,endkeys,gt=0" swaggertype:"object,string"`
+	StockQuantity int                    `json:"stock_quantity" binding:"gte=0"`
+}
+
+// SummarizeVariants fills the fields derived from the variants: the option
+// axes and their values, and TotalStock and TotalReserved, which are the sums
+// of the variant stock and reservations for products with variants and the
+// product's own otherwise, along with the StockStatus they result in.
+func (p *Product) SummarizeVariants() {
+	p.Options = nil
+	p.TotalStock = p.StockQuantity
+	p.TotalReserved = p.ReservedQuantity
+	p.TotalAvailable = p.TotalStock - p.TotalReserved
+	p.StockStatus = StockStatusFor(p.TotalAvailable, p.ReorderPoint)
+	if len(p.Variants) == 0 {
+		return
+	}
+
+	values := make(map[string]map[string]bool)
+	p.TotalStock = 0
+	p.TotalReserved = 0
+	for i := range p.Variants {
+		variant := &p.Variants[i]
+		variant.AvailableQuantity = variant.StockQuantity - variant.ReservedQuantity
+		p.TotalStock += variant.StockQuantity
+		p.TotalReserved += variant.ReservedQuantity
+		for name, value := range variant.Options {
+			if values[name] == nil {
+				values[name] = make(map[string]bool)
+			}
+			values[name][value] = true
+		}
+	}
+
+	p.TotalAvailable = p.TotalStock - p.TotalReserved
+	p.StockStatus = StockStatusFor(p.TotalAvailable, p.ReorderPoint)
+
+	for name, set := range values {
+		option := ProductOption{Name: name}
+		for value := range set {
+			option.Values = append(option.Values, value)
+		}
+		sort.Strings(option.Values)
+		p.Options = append(p.Options, option)
+	}
+	sort.Slice(p.Options, func(i, j int) bool {
+		return p.Options[i].Name < p.Options[j].Name
+	})
+}
+
+// MatchesOptions reports whether the variant has every option in options.
+func (v ProductVariant) MatchesOptions(options map[string]string) bool {
+	for name, value := range options {
+		if v.Options[name] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,27 @@
+package money
+
+import "strings"
+
+// otherScaleCurrencies are the ISO 4217 currencies whose minor unit is not the
+// hundredth: those without decimals, those with three or four, and the funds
+// and precious metals, which have no minor unit at all. Money has a fixed
+// scale of two decimal places and cannot hold their amounts exactly.
+var otherScaleCurrencies = map[string]bool{
+	// No decimals.
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+	"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "UYI": true,
+	"VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+	// Three decimals.
+	"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true,
+	// Four decimals.
+	"CLF": true, "UYW": true,
+	// No minor unit.
+	"XAG": true, "XAU": true, "XBA": true, "XBB": true, "XBC": true, "XBD": true,
+	"XDR": true, "XPD": true, "XPT": true, "XSU": true, "XTS": true, "XUA": true, "XXX": true,
+}
+
+// SupportsCurrency reports whether amounts in the ISO 4217 currency have two
+// decimal places and therefore fit in Money.
+func SupportsCurrency(currency string) bool {
+	return !otherScaleCurrencies[strings.ToUpper(currency)]
+}
@@ -0,0 +1,73 @@
+package money
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+type JSONFormat int32
+
+const (
+	// FormatString encodes amounts as decimal strings, e.g. "2999.99".
+	FormatString JSONFormat = iota
+	// FormatMinorUnits encodes amounts as integer minor units, e.g. 299999.
+	FormatMinorUnits
+)
+
+var jsonFormat atomic.Int32
+
+func ParseJSONFormat(s string) (JSONFormat, error) {
+	switch s {
+	case "string", "":
+		return FormatString, nil
+	case "minor":
+		return FormatMinorUnits, nil
+	default:
+		return FormatString, fmt.Errorf("formato JSON de valores monetários inválido: %q (use string ou minor)", s)
+	}
+}
+
+// SetJSONFormat selects how every Money value is encoded and how JSON numbers are decoded.
+func SetJSONFormat(format JSONFormat) {
+	jsonFormat.Store(int32(format))
+}
+
+func CurrentJSONFormat() JSONFormat {
+	return JSONFormat(jsonFormat.Load())
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	if CurrentJSONFormat() == FormatMinorUnits {
+		return []byte(strconv.FormatInt(m.cents, 10)), nil
+	}
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// UnmarshalJSON accepts a decimal string in any format. JSON numbers are read
+// as minor units in FormatMinorUnits and as exact decimal amounts otherwise.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+
+	if len(text) > 0 && text[0] == '"' {
+		unquoted, err := strconv.Unquote(text)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, text)
+		}
+		return m.parseInto(unquoted)
+	}
+
+	if CurrentJSONFormat() == FormatMinorUnits {
+		cents, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: esperado inteiro em centavos, recebido %s", ErrInvalidAmount, text)
+		}
+		m.cents = cents
+		return nil
+	}
+
+	return m.parseInto(text)
+}
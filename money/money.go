@@ -0,0 +1,144 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount with two decimal places, stored as minor units
+// (cents) so values never go through binary floating point.
+type Money struct {
+	cents int64
+}
+
+var (
+	ErrInvalidAmount    = errors.New("valor monetário inválido")
+	ErrTooManyDecimals  = errors.New("valor monetário com mais de duas casas decimais")
+	ErrAmountOutOfRange = errors.New("valor monetário fora do intervalo permitido")
+)
+
+const maxIntegerDigits = 16
+
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// Parse reads a decimal amount such as "2999.99". Trailing zeros beyond the
+// second decimal place are accepted, any other extra digit is rejected.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	integerPart, rawFraction, _ := strings.Cut(s, ".")
+	if (integerPart == "" && rawFraction == "") || !isDigits(integerPart) || !isDigits(rawFraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	fractionPart := strings.TrimRight(rawFraction, "0")
+	if len(fractionPart) > 2 {
+		return Money{}, fmt.Errorf("%w: %q", ErrTooManyDecimals, s)
+	}
+	if len(strings.TrimLeft(integerPart, "0")) > maxIntegerDigits {
+		return Money{}, fmt.Errorf("%w: %q", ErrAmountOutOfRange, s)
+	}
+
+	fractionPart += strings.Repeat("0", 2-len(fractionPart))
+	if integerPart == "" {
+		integerPart = "0"
+	}
+
+	cents, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrAmountOutOfRange, s)
+	}
+
+	if negative {
+		cents = -cents
+	}
+	return Money{cents: cents}, nil
+}
+
+// MustParse is Parse for constants known to be valid; it panics otherwise.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// Cmp returns -1, 0 or +1 depending on whether m is less than, equal to or greater than other.
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.cents < other.cents:
+		return -1
+	case m.cents > other.cents:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) String() string {
+	cents := m.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Scan implements sql.Scanner for DECIMAL/NUMERIC columns.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return m.parseInto(string(v))
+	case string:
+		return m.parseInto(v)
+	case int64:
+		m.cents = v * 100
+		return nil
+	default:
+		return fmt.Errorf("tipo não suportado para valor monetário: %T", src)
+	}
+}
+
+// Value implements driver.Valuer, sending the amount as an exact decimal literal.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) parseInto(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
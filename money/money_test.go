@@ -0,0 +1,133 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		cents   int64
+		wantErr error
+	}{
+		{input: "2999.99", cents: 299999},
+		{input: "1.230", cents: 123},
+		{input: "1.2", cents: 120},
+		{input: "10", cents: 1000},
+		{input: ".5", cents: 50},
+		{input: " 7.00 ", cents: 700},
+		{input: "+3.10", cents: 310},
+		{input: "-10.50", cents: -1050},
+		{input: "-0.5", cents: -50},
+		{input: "9999999999999999.99", cents: 999999999999999999},
+		{input: "1.234", wantErr: ErrTooManyDecimals},
+		{input: "10.555", wantErr: ErrTooManyDecimals},
+		{input: "99999999999999999", wantErr: ErrAmountOutOfRange},
+		{input: "99999999999999999999999", wantErr: ErrAmountOutOfRange},
+		{input: "1e3", wantErr: ErrInvalidAmount},
+		{input: "1,50", wantErr: ErrInvalidAmount},
+		{input: "", wantErr: ErrInvalidAmount},
+		{input: "-", wantErr: ErrInvalidAmount},
+		{input: ".", wantErr: ErrInvalidAmount},
+		{input: "--1", wantErr: ErrInvalidAmount},
+		{input: "abc", wantErr: ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got.Cents() != tt.cents {
+				t.Errorf("Parse(%q) = %d cents, want %d", tt.input, got.Cents(), tt.cents)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[int64]string{
+		0:      "0.00",
+		5:      "0.05",
+		-50:    "-0.50",
+		123:    "1.23",
+		299999: "2999.99",
+	}
+	for cents, want := range tests {
+		if got := FromCents(cents).String(); got != want {
+			t.Errorf("FromCents(%d).String() = %q, want %q", cents, got, want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	previous := CurrentJSONFormat()
+	t.Cleanup(func() { SetJSONFormat(previous) })
+
+	tests := []struct {
+		format  JSONFormat
+		input   string
+		cents   int64
+		encoded string
+		wantErr bool
+	}{
+		{format: FormatString, input: `"2999.99"`, cents: 299999, encoded: `"2999.99"`},
+		{format: FormatString, input: `2999.99`, cents: 299999, encoded: `"2999.99"`},
+		{format: FormatString, input: `1.234`, wantErr: true},
+		{format: FormatMinorUnits, input: `299999`, cents: 299999, encoded: `299999`},
+		{format: FormatMinorUnits, input: `"2999.99"`, cents: 299999, encoded: `299999`},
+		{format: FormatMinorUnits, input: `2999.99`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		SetJSONFormat(tt.format)
+
+		var m Money
+		err := m.UnmarshalJSON([]byte(tt.input))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("format %d: UnmarshalJSON(%s) succeeded, want error", tt.format, tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("format %d: UnmarshalJSON(%s) error = %v", tt.format, tt.input, err)
+			continue
+		}
+		if m.Cents() != tt.cents {
+			t.Errorf("format %d: UnmarshalJSON(%s) = %d cents, want %d", tt.format, tt.input, m.Cents(), tt.cents)
+		}
+
+		encoded, err := m.MarshalJSON()
+		if err != nil || string(encoded) != tt.encoded {
+			t.Errorf("format %d: MarshalJSON() = %s, %v; want %s", tt.format, encoded, err, tt.encoded)
+		}
+	}
+}
+
+func TestSupportsCurrency(t *testing.T) {
+	tests := map[string]bool{
+		"BRL": true,
+		"usd": true,
+		"EUR": true,
+		"JPY": false,
+		"krw": false,
+		"KWD": false,
+		"BHD": false,
+		"CLF": false,
+		"XAU": false,
+	}
+	for currency, want := range tests {
+		if got := SupportsCurrency(currency); got != want {
+			t.Errorf("SupportsCurrency(%s) = %v, want %v", currency, got, want)
+		}
+	}
+}
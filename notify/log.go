@@ -0,0 +1,18 @@
+package notify
+
+import (
+	"context"
+	"log"
+)
+
+// Log writes alerts to the application log.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (n *Log) Notify(ctx context.Context, alert Alert) error {
+	log.Printf("Alerta de estoque: %s", alert.Subject())
+	return nil
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// Alert reports that the stock of a product got worse: it reached its reorder
+// point or ran out.
+type Alert struct {
+	Product        models.LowStockItem `json:"product"`
+	PreviousStatus string              `json:"previous_status"`
+	OccurredAt     time.Time           `json:"occurred_at"`
+}
+
+// Subject is a one line summary of the alert, used in logs and e-mails.
+func (a Alert) Subject() string {
+	name := a.Product.Name
+	if a.Product.SKU != "" {
+		name += " (" + a.Product.SKU + ")"
+	}
+	if a.Product.StockStatus == models.StockStatusOutOfStock {
+		return fmt.Sprintf("Produto %d %s sem estoque", a.Product.ProductID, name)
+	}
+	return fmt.Sprintf("Produto %d %s com estoque baixo: %d disponível(is), ponto de reposição %d",
+		a.Product.ProductID, name, a.Product.TotalAvailable, a.Product.ReorderPoint)
+}
+
+// Notifier delivers stock alerts. Notify returns an error when the alert
+// could not be delivered, so the caller can try again later.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// SMTP e-mails alerts through a local relay at addr, without authentication.
+type SMTP struct {
+	addr string
+	from string
+	to   []string
+}
+
+func NewSMTP(addr, from string, to []string) (*SMTP, error) {
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("remetente e destinatários dos alertas de estoque por e-mail são obrigatórios")
+	}
+	return &SMTP{addr: addr, from: from, to: to}, nil
+}
+
+func (n *SMTP) Notify(ctx context.Context, alert Alert) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	subject := alert.Subject()
+	body := fmt.Sprintf("%s\n\nEstoque total: %d\nReservado: %d\nDisponível: %d\nPonto de reposição: %d\nQuantidade de reposição: %d\n",
+		subject, alert.Product.TotalStock, alert.Product.TotalReserved, alert.Product.TotalAvailable,
+		alert.Product.ReorderPoint, alert.Product.ReorderQuantity)
+
+	message := "From: " + n.from + "\r\n" +
+		"To: " + strings.Join(n.to, ", ") + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + strings.ReplaceAll(body, "\n", "\r\n")
+
+	return smtp.SendMail(n.addr, nil, n.from, n.to, []byte(message))
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+const categoryColumns = `id, slug, name, parent_id, created_at, updated_at`
+
+type CategoryRepository struct {
+	db       *sql.DB
+	timeouts QueryTimeouts
+}
+
+func NewCategoryRepository(db *sql.DB, timeouts QueryTimeouts) *CategoryRepository {
+	return &CategoryRepository{db: db, timeouts: timeouts}
+}
+
+func scanCategory(row rowScanner) (models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.ID,
+		&category.Slug,
+		&category.Name,
+		&category.ParentID,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	return category, err
+}
+
+func (r *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT `+categoryColumns+` FROM categories ORDER BY name, id`)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return categories, nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	category, err := scanCategory(r.db.QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("categoria com ID %d não encontrada", id)
+		}
+		return nil, translateError(err)
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	category, err := scanCategory(r.db.QueryRowContext(ctx, `SELECT `+categoryColumns+` FROM categories WHERE slug = $1`, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("categoria %s não encontrada", slug)
+		}
+		return nil, translateError(err)
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, req models.CategoryRequest) (*models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var category models.Category
+	err := r.withHierarchyLock(ctx, func(tx *sql.Tx) error {
+		if err := r.checkParent(ctx, tx, 0, req.ParentID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var err error
+		category, err = scanCategory(tx.QueryRowContext(ctx, `
+			INSERT INTO categories (slug, name, parent_id, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+categoryColumns, req.Slug, req.Name, req.ParentID, now, now))
+		return translateError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, id int, req models.CategoryRequest) (*models.Category, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var category models.Category
+	err := r.withHierarchyLock(ctx, func(tx *sql.Tx) error {
+		if err := r.checkParent(ctx, tx, id, req.ParentID); err != nil {
+			return err
+		}
+
+		var err error
+		category, err = scanCategory(tx.QueryRowContext(ctx, `
+			UPDATE categories
+			SET slug = $1, name = $2, parent_id = $3, updated_at = $4
+			WHERE id = $5
+			RETURNING `+categoryColumns, req.Slug, req.Name, req.ParentID, time.Now(), id))
+		if err == sql.ErrNoRows {
+			return apperrors.NotFound("categoria com ID %d não encontrada", id)
+		}
+		return translateError(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// Delete only removes categories without subcategories or products, including
+// products in the trash.
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.withHierarchyLock(ctx, func(tx *sql.Tx) error {
+		var hasChildren, hasProducts bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT
+				EXISTS (SELECT 1 FROM categories WHERE parent_id = $1),
+				EXISTS (SELECT 1 FROM products WHERE category_id = $1)
+		`, id).Scan(&hasChildren, &hasProducts)
+		if err != nil {
+			return translateError(err)
+		}
+		if hasChildren {
+			return apperrors.Conflict("categoria com ID %d possui subcategorias", id)
+		}
+		if hasProducts {
+			return apperrors.Conflict("categoria com ID %d possui produtos", id)
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+		if err != nil {
+			return translateError(err)
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return apperrors.NotFound("categoria com ID %d não encontrada", id)
+		}
+		return nil
+	})
+}
+
+// withHierarchyLock runs fn in a transaction that serializes changes to the
+// category tree, so two concurrent moves can never create a cycle.
+func (r *CategoryRepository) withHierarchyLock(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return translateError(err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return translateError(tx.Commit())
+}
+
+// checkParent validates that parentID exists and is not the category itself
+// or one of its descendants.
+func (r *CategoryRepository) checkParent(ctx context.Context, tx *sql.Tx, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+
+	var exists, cycle bool
+	err := tx.QueryRowContext(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id FROM categories WHERE id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+		)
+		SELECT
+			EXISTS (SELECT 1 FROM ancestors),
+			EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+	`, *parentID, id).Scan(&exists, &cycle)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if !exists {
+		return apperrors.Validation("categoria pai com ID %d não encontrada", *parentID)
+	}
+	if cycle {
+		return apperrors.Validation("a categoria %d não pode ficar abaixo de si mesma ou de uma subcategoria", id)
+	}
+	return nil
+}
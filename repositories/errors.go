@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/apperrors"
+)
+
+// uniqueViolations describes the unique indexes whose violation a client can fix.
+var uniqueViolations = map[string]string{
+	"idx_products_sku":             "já existe um produto com este SKU",
+	"idx_products_gtin":            "já existe um produto com este GTIN",
+	"categories_slug_key":          "já existe uma categoria com este slug",
+	"idx_product_variants_sku":     "já existe uma variante com este SKU",
+	"idx_product_variants_options": "já existe uma variante com essas opções",
+}
+
+// translateError converts driver errors into the domain errors from apperrors
+// so handlers never need to inspect PostgreSQL error codes.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return apperrors.Timeout(err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return apperrors.Unavailable(err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "57014":
+			return apperrors.Timeout(err)
+		case pqErr.Code == "23505":
+			if message, ok := uniqueViolations[pqErr.Constraint]; ok {
+				return apperrors.Wrap(apperrors.ErrConflict, err, message)
+			}
+			return apperrors.Wrap(apperrors.ErrConflict, err, "registro duplicado")
+		case pqErr.Code == "23503":
+			return apperrors.Wrap(apperrors.ErrConflict, err, "registro referenciado por outro recurso")
+		case pqErr.Code.Class() == "23", pqErr.Code.Class() == "22":
+			return apperrors.Wrap(apperrors.ErrValidation, err, "valor rejeitado pelo banco de dados")
+		case pqErr.Code.Class() == "08", pqErr.Code.Class() == "53", pqErr.Code.Class() == "57":
+			return apperrors.Unavailable(err)
+		}
+		return err
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return apperrors.Unavailable(err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return apperrors.Unavailable(err)
+	}
+
+	return err
+}
+
+// stateConflict builds the error for a guarded write on a product that exists
+// but did not match the expected trash state or version.
+func stateConflict(id, version int, deleted, expectDeleted bool) error {
+	if deleted != expectDeleted {
+		if expectDeleted {
+			return apperrors.Conflict("produto com ID %d não está na lixeira", id)
+		}
+		return apperrors.NotFound("produto com ID %d não encontrado", id)
+	}
+
+	return apperrors.PreconditionFailed("produto com ID %d foi modificado (versão atual %d)", id, version)
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// testFacets checks that each facet counts the products matching every filter
+// but its own.
+func testFacets(t *testing.T, store ProductStore, categories CategoryStore) {
+	ctx := context.Background()
+	grills := createCategory(t, categories, "grelhas-facetadas", "Grelhas Facetadas")
+	pans := createCategory(t, categories, "panelas-facetadas", "Panelas Facetadas")
+	brl := func(amount string) map[string]money.Money {
+		return map[string]money.Money{"BRL": money.MustParse(amount)}
+	}
+	createListed(t, store, models.CreateProductRequest{Name: "Grelha Facetada P", Prices: brl("50.00"), CategoryID: &grills.ID, StockQuantity: 10, ReorderPoint: 2})
+	createListed(t, store, models.CreateProductRequest{Name: "Grelha Facetada G", Prices: brl("150.00"), CategoryID: &grills.ID, StockQuantity: 2, ReorderPoint: 2})
+	createListed(t, store, models.CreateProductRequest{Name: "Panela Facetada", Prices: brl("99.99"), CategoryID: &pans.ID})
+	createListed(t, store, models.CreateProductRequest{Name: "Frigideira Facetada", Prices: brl("100.00"), CategoryID: &pans.ID, StockQuantity: 5})
+	trashed := createListed(t, store, models.CreateProductRequest{Name: "Tampa Facetada", Prices: brl("20.00"), CategoryID: &pans.ID, StockQuantity: 5})
+	if err := store.Delete(ctx, trashed.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	request := models.FacetRequest{
+		Category:     true,
+		Price:        true,
+		Stock:        true,
+		PriceBuckets: []money.Money{money.MustParse("100.00")},
+	}
+	minPrice := money.MustParse("100.00")
+	tests := []struct {
+		name       string
+		filter     models.ProductFilter
+		categories map[string]int
+		prices     []int
+		stock      []int
+	}{
+		{
+			name:       "no filter",
+			filter:     models.ProductFilter{},
+			categories: map[string]int{"grelhas-facetadas": 2, "panelas-facetadas": 2},
+			prices:     []int{2, 2},
+			stock:      []int{2, 1, 1},
+		},
+		{
+			name:       "category",
+			filter:     models.ProductFilter{CategoryIDs: []int{pans.ID}},
+			categories: map[string]int{"grelhas-facetadas": 2, "panelas-facetadas": 2},
+			prices:     []int{1, 1},
+			stock:      []int{1, 0, 1},
+		},
+		{
+			name:       "price",
+			filter:     models.ProductFilter{MinPrice: &minPrice},
+			categories: map[string]int{"grelhas-facetadas": 1, "panelas-facetadas": 1},
+			prices:     []int{2, 2},
+			stock:      []int{1, 1, 0},
+		},
+		{
+			name:       "stock status",
+			filter:     models.ProductFilter{StockStatus: models.StockStatusInStock},
+			categories: map[string]int{"grelhas-facetadas": 1, "panelas-facetadas": 1},
+			prices:     []int{1, 1},
+			stock:      []int{2, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		filter := tt.filter
+		filter.Name = "Facetad"
+		filter.Currency = "BRL"
+		facets, err := store.Facets(ctx, filter, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]int)
+		for _, category := range facets.Categories {
+			got[category.Slug] = category.Count
+		}
+		if len(got) != len(tt.categories) {
+			t.Errorf("%s: categories = %v, want %v", tt.name, got, tt.categories)
+		}
+		for slug, count := range tt.categories {
+			if got[slug] != count {
+				t.Errorf("%s: categories = %v, want %v", tt.name, got, tt.categories)
+				break
+			}
+		}
+		if len(facets.PriceRanges) != len(tt.prices) {
+			t.Fatalf("%s: price ranges = %+v", tt.name, facets.PriceRanges)
+		}
+		for i, count := range tt.prices {
+			if facets.PriceRanges[i].Count != count {
+				t.Errorf("%s: price range %d counts %d, want %d", tt.name, i, facets.PriceRanges[i].Count, count)
+			}
+		}
+		if len(facets.StockStatus) != len(tt.stock) {
+			t.Fatalf("%s: stock status = %+v", tt.name, facets.StockStatus)
+		}
+		for i, count := range tt.stock {
+			if facets.StockStatus[i].Count != count {
+				t.Errorf("%s: %s counts %d, want %d", tt.name, facets.StockStatus[i].Status, facets.StockStatus[i].Count, count)
+			}
+		}
+	}
+}
+
+func TestMemoryFacets(t *testing.T) {
+	store := NewMemoryProductRepository()
+	testFacets(t, store, NewMemoryCategoryRepository(store))
+}
+
+func TestPostgresFacets(t *testing.T) {
+	store := postgresStore(t)
+	testFacets(t, store, NewCategoryRepository(store.db, QueryTimeouts{}))
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"cabo":       "cabo",
+		"100%":       `100\%`,
+		"cabo_usb":   `cabo\_usb`,
+		`C:\dados`:   `C:\\dados`,
+		`\%_`:        `\\\%\_`,
+		"Álcool 70%": `Álcool 70\%`,
+	}
+	for text, want := range tests {
+		if got := escapeLike(text); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+// testNameFilter checks that the name filter matches the typed text anywhere
+// in the name, ignoring case, with no wildcards.
+func testNameFilter(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	names := []string{
+		"Toalha 100% Algodão",
+		"Toalha 1000 Fios",
+		"Cabo_USB Reforçado",
+		"Cabo-USB Simples",
+		`Pendrive C:\Backup`,
+		`Pendrive C:Backup`,
+	}
+	ids := make(map[int]string)
+	for _, name := range names {
+		ids[createListed(t, store, models.CreateProductRequest{Name: name}).ID] = name
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"toalha", []string{"Toalha 100% Algodão", "Toalha 1000 Fios"}},
+		{"TOALHA 100", []string{"Toalha 100% Algodão", "Toalha 1000 Fios"}},
+		{"100%", []string{"Toalha 100% Algodão"}},
+		{"%", []string{"Toalha 100% Algodão"}},
+		{"cabo_usb", []string{"Cabo_USB Reforçado"}},
+		{"_", []string{"Cabo_USB Reforçado"}},
+		{`c:\backup`, []string{`Pendrive C:\Backup`}},
+		{`\`, []string{`Pendrive C:\Backup`}},
+	}
+	for _, tt := range tests {
+		products, _, err := store.FindByFilter(ctx, models.ProductFilter{Name: tt.filter, Currency: "BRL"}, models.NextTokenRequest{Limit: 100}, models.CountNone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, product := range products {
+			if name, ok := ids[product.ID]; ok {
+				got = append(got, name)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("name %q matched %q, want %q", tt.filter, got, tt.want)
+			continue
+		}
+		for _, name := range tt.want {
+			found := false
+			for _, g := range got {
+				found = found || g == name
+			}
+			if !found {
+				t.Errorf("name %q matched %q, want %q", tt.filter, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMemoryNameFilter(t *testing.T) {
+	testNameFilter(t, NewMemoryProductRepository())
+}
+
+func TestPostgresNameFilter(t *testing.T) {
+	testNameFilter(t, postgresStore(t))
+}
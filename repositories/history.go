@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/audit"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// ignoredHistoryFields change on every write, or are derived from other
+// fields, and would only add noise to the diff.
+var ignoredHistoryFields = map[string]bool{
+	"version":         true,
+	"updated_at":      true,
+	"total_stock":     true,
+	"total_reserved":  true,
+	"total_available": true,
+	"stock_status":    true,
+}
+
+func newHistoryEntry(ctx context.Context, productID int, action string, before, after *models.Product) (models.ProductHistory, error) {
+	changes, err := diffProducts(before, after)
+	if err != nil {
+		return models.ProductHistory{}, err
+	}
+
+	return models.ProductHistory{
+		ProductID: productID,
+		Action:    action,
+		Actor:     audit.ActorFromContext(ctx),
+		RequestID: audit.RequestIDFromContext(ctx),
+		Before:    before,
+		After:     after,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func diffProducts(before, after *models.Product) (map[string]models.FieldChange, error) {
+	beforeFields, err := productFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := productFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.FieldChange)
+	for field, value := range afterFields {
+		if !ignoredHistoryFields[field] && !reflect.DeepEqual(beforeFields[field], value) {
+			changes[field] = models.FieldChange{From: beforeFields[field], To: value}
+		}
+	}
+	for field, value := range beforeFields {
+		if _, ok := afterFields[field]; !ok && !ignoredHistoryFields[field] {
+			changes[field] = models.FieldChange{From: value, To: nil}
+		}
+	}
+
+	return changes, nil
+}
+
+// productFields encodes product the way history rows store it, with amounts
+// as decimal strings.
+func productFields(product *models.Product) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if product == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeJSON(data, &fields); err != nil {
+		return nil, err
+	}
+
+	stored, err := decimalAmounts(fields, productType)
+	if err != nil {
+		return nil, err
+	}
+	return stored.(map[string]interface{}), nil
+}
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// History snapshots and changes are stored with amounts as decimal strings,
+// whatever MONEY_JSON_FORMAT says, so rows stay readable when the format
+// changes. Reading them back uses the normal Money JSON rules, which accept
+// decimal strings in every format; the format only applies when the history
+// is sent to clients.
+
+var (
+	moneyType   = reflect.TypeOf(money.Money{})
+	productType = reflect.TypeOf(models.Product{})
+)
+
+// decodeJSON reads data keeping numbers as json.Number, so amounts in minor
+// units don't lose precision.
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// decimalAmounts walks value, a JSON value of type t decoded by decodeJSON,
+// and returns a copy with every amount as a decimal string. Money only
+// writes numbers, in minor units, in FormatMinorUnits.
+func decimalAmounts(value interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil {
+		return nil, nil
+	}
+	if t == moneyType {
+		number, ok := value.(json.Number)
+		if !ok {
+			return value, nil
+		}
+		cents, err := strconv.ParseInt(string(number), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", money.ErrInvalidAmount, number)
+		}
+		return money.FromCents(cents).String(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		copied := make(map[string]interface{}, len(object))
+		for key, item := range object {
+			copied[key] = item
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := jsonFieldName(field)
+			if fieldValue, ok := copied[name]; ok && name != "" {
+				converted, err := decimalAmounts(fieldValue, field.Type)
+				if err != nil {
+					return nil, err
+				}
+				copied[name] = converted
+			}
+		}
+		return copied, nil
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil
+		}
+		copied := make(map[string]interface{}, len(object))
+		for key, item := range object {
+			converted, err := decimalAmounts(item, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			copied[key] = converted
+		}
+		return copied, nil
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		copied := make([]interface{}, len(items))
+		for i, item := range items {
+			converted, err := decimalAmounts(item, t.Elem())
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = converted
+		}
+		return copied, nil
+	}
+	return value, nil
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// productFieldType returns the type of the Product field encoded as name.
+func productFieldType(name string) (reflect.Type, bool) {
+	for i := 0; i < productType.NumField(); i++ {
+		if jsonFieldName(productType.Field(i)) == name {
+			return productType.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
+
+// presentChanges decodes the values of stored changes into the types of the
+// product fields, so amounts are encoded in the configured format when sent
+// to clients.
+func presentChanges(changes map[string]models.FieldChange) (map[string]models.FieldChange, error) {
+	presented := make(map[string]models.FieldChange, len(changes))
+	for field, change := range changes {
+		if t, ok := productFieldType(field); ok {
+			from, err := typedValue(change.From, t)
+			if err != nil {
+				return nil, err
+			}
+			to, err := typedValue(change.To, t)
+			if err != nil {
+				return nil, err
+			}
+			change = models.FieldChange{From: from, To: to}
+		}
+		presented[field] = change
+	}
+	return presented, nil
+}
+
+func typedValue(value interface{}, t reflect.Type) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	typed := reflect.New(t)
+	if err := json.Unmarshal(data, typed.Interface()); err != nil {
+		return nil, err
+	}
+	return typed.Elem().Interface(), nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+func withJSONFormat(t *testing.T, format money.JSONFormat) {
+	t.Helper()
+	previous := money.CurrentJSONFormat()
+	money.SetJSONFormat(format)
+	t.Cleanup(func() { money.SetJSONFormat(previous) })
+}
+
+func TestStoredSnapshotIgnoresJSONFormat(t *testing.T) {
+	price := money.MustParse("2999.99")
+	variantPrice := money.MustParse("10.50")
+	product := &models.Product{
+		ID:     1,
+		Name:   "Smartphone",
+		Price:  &price,
+		Prices: map[string]money.Money{"BRL": price},
+		Variants: []models.ProductVariant{
+			{ID: 2, Price: &variantPrice, Prices: map[string]money.Money{"USD": variantPrice}},
+		},
+	}
+
+	var stored []string
+	for _, format := range []money.JSONFormat{money.FormatString, money.FormatMinorUnits} {
+		withJSONFormat(t, format)
+		data, err := nullableJSON(product)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored = append(stored, data.(string))
+	}
+
+	if stored[0] != stored[1] {
+		t.Fatalf("snapshot depends on the JSON format:\n%s\n%s", stored[0], stored[1])
+	}
+
+	var fields struct {
+		Price    string            `json:"price"`
+		Prices   map[string]string `json:"prices"`
+		Variants []struct {
+			Price  string            `json:"price"`
+			Prices map[string]string `json:"prices"`
+		} `json:"variants"`
+	}
+	if err := json.Unmarshal([]byte(stored[0]), &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields.Price != "2999.99" || fields.Prices["BRL"] != "2999.99" {
+		t.Errorf("product amounts = %q, %v; want decimal strings", fields.Price, fields.Prices)
+	}
+	if fields.Variants[0].Price != "10.50" || fields.Variants[0].Prices["USD"] != "10.50" {
+		t.Errorf("variant amounts = %q, %v; want decimal strings", fields.Variants[0].Price, fields.Variants[0].Prices)
+	}
+}
+
+func TestDecodeSnapshot(t *testing.T) {
+	// Rows are written with decimal strings, which read the same in every format.
+	for _, format := range []money.JSONFormat{money.FormatString, money.FormatMinorUnits} {
+		withJSONFormat(t, format)
+		var product models.Product
+		if err := json.Unmarshal([]byte(`{"id": 1, "price": "2999.99", "prices": {"BRL": "2999.99"}}`), &product); err != nil {
+			t.Fatal(err)
+		}
+		if product.Price.String() != "2999.99" || product.Prices["BRL"].String() != "2999.99" {
+			t.Errorf("format %d: amounts = %s, %v; want 2999.99", format, product.Price, product.Prices)
+		}
+	}
+}
+
+// A row written at user-009 with MONEY_JSON_FORMAT=minor holds the price in
+// minor units and has no prices field. It is read with the same format.
+func TestDecodeMinorUnitsSnapshot(t *testing.T) {
+	withJSONFormat(t, money.FormatMinorUnits)
+	var product models.Product
+	if err := json.Unmarshal([]byte(`{"id": 1, "name": "Smartphone", "price": 299999}`), &product); err != nil {
+		t.Fatal(err)
+	}
+	if product.Price == nil || product.Price.String() != "2999.99" {
+		t.Errorf("price = %v, want 2999.99", product.Price)
+	}
+
+	var stored map[string]models.FieldChange
+	if err := decodeJSON([]byte(`{"price": {"from": 299999, "to": 1050}}`), &stored); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := presentChanges(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from := changes["price"].From.(*money.Money); from.String() != "2999.99" {
+		t.Errorf("from = %s, want 2999.99", from)
+	}
+}
+
+func TestPresentChanges(t *testing.T) {
+	var stored map[string]models.FieldChange
+	data := []byte(`{"prices": {"from": {"BRL": "2999.99"}, "to": {"BRL": "10.50"}}, "name": {"from": "A", "to": "B"}}`)
+	if err := decodeJSON(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := presentChanges(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withJSONFormat(t, money.FormatMinorUnits)
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":{"from":"A","to":"B"},"prices":{"from":{"BRL":299999},"to":{"BRL":1050}}}`
+	if string(encoded) != want {
+		t.Errorf("changes = %s, want %s", encoded, want)
+	}
+
+	// The stored changes must not be modified.
+	if stored["prices"].From.(map[string]interface{})["BRL"] != "2999.99" {
+		t.Errorf("presentChanges modified the stored changes: %v", stored["prices"].From)
+	}
+}
+
+func TestDiffProductsIgnoresDerivedFields(t *testing.T) {
+	before := &models.Product{ID: 1, StockQuantity: 10, Version: 1}
+	before.SummarizeVariants()
+	after := *before
+	after.StockQuantity = 0
+	after.Version = 2
+	after.SummarizeVariants()
+
+	changes, err := diffProducts(before, &after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes["stock_quantity"].To == nil {
+		t.Errorf("changes = %v, want only stock_quantity", changes)
+	}
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// sortKeys builds the keys of a sort such as "price:asc,name:desc", with the
+// id appended in the direction of the last field, like the handler does.
+func sortKeys(sort string) []models.SortKey {
+	var keys []models.SortKey
+	for _, part := range strings.Split(sort, ",") {
+		field, direction, _ := strings.Cut(part, ":")
+		keys = append(keys, models.SortKey{Field: field, Desc: direction == "desc"})
+	}
+	if keys[len(keys)-1].Field != models.SortID {
+		keys = append(keys, models.SortKey{Field: models.SortID, Desc: keys[len(keys)-1].Desc})
+	}
+	return keys
+}
+
+// cursorAfter sets the After values of keys to the fields of product.
+func cursorAfter(keys []models.SortKey, product models.Product, currency string) []models.SortKey {
+	after := make([]models.SortKey, len(keys))
+	for i, key := range keys {
+		key.After = sortFieldValue(product, key.Field, currency)
+		after[i] = key
+	}
+	return after
+}
+
+// testKeysetPagination walks every sort two products at a time and checks
+// that the pages follow the expected order without gaps or repetitions.
+func testKeysetPagination(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	prefix := fmt.Sprintf("keyset%d ", time.Now().UnixNano())
+	brl := func(amount string) map[string]money.Money {
+		return map[string]money.Money{"BRL": money.MustParse(amount)}
+	}
+
+	// Names are compared by code point, so "Zebra" < "abacate" < "Água".
+	products := []struct {
+		label string
+		req   models.CreateProductRequest
+	}{
+		{"A", models.CreateProductRequest{Name: prefix + "Zebra", Prices: brl("10.00"), StockQuantity: 5}},
+		{"B", models.CreateProductRequest{Name: prefix + "abacate", Prices: brl("10.00"), StockQuantity: 5}},
+		{"C", models.CreateProductRequest{Name: prefix + "Água", Prices: brl("20.00"), StockQuantity: 1}},
+		{"D", models.CreateProductRequest{Name: prefix + "banana", Prices: brl("10.00"), StockQuantity: 3}},
+		{"E", models.CreateProductRequest{Name: prefix + "Água", Prices: brl("5.00"), StockQuantity: 1}},
+		// Without a price in BRL, so never part of the results.
+		{"F", models.CreateProductRequest{Name: prefix + "Uva", Prices: map[string]money.Money{"USD": money.MustParse("1.00")}, StockQuantity: 9}},
+	}
+	labels := make(map[int]string)
+	for _, p := range products {
+		created, err := store.Create(ctx, p.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		labels[created.ID] = p.label
+		t.Cleanup(func() { store.Delete(context.Background(), created.ID, nil) })
+	}
+
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"name:asc", "ABDCE"},
+		{"name:desc", "ECDBA"},
+		{"price:asc", "EABDC"},
+		{"price:asc,name:desc", "EDBAC"},
+		{"price:desc,stock:asc", "CDABE"},
+		{"stock:asc,price:desc,name:asc", "CEDAB"},
+		{"stock:desc,name:asc", "ABDCE"},
+	}
+
+	filter := models.ProductFilter{Name: prefix, Currency: "BRL"}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			keys := sortKeys(tt.sort)
+			var got strings.Builder
+			for pages := 0; ; pages++ {
+				if pages > len(products) {
+					t.Fatalf("pagination does not end: %s", got.String())
+				}
+				page, _, err := store.FindByFilter(ctx, filter, models.NextTokenRequest{SortKeys: keys, Limit: 2}, models.CountNone)
+				if err != nil {
+					t.Fatal(err)
+				}
+				hasMore := len(page) > 2
+				if hasMore {
+					page = page[:2]
+				}
+				for _, product := range page {
+					got.WriteString(labels[product.ID])
+				}
+				if !hasMore {
+					break
+				}
+				keys = cursorAfter(keys, page[len(page)-1], filter.Currency)
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("order = %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryKeysetPagination(t *testing.T) {
+	testKeysetPagination(t, NewMemoryProductRepository())
+}
+
+func TestPostgresKeysetPagination(t *testing.T) {
+	testKeysetPagination(t, postgresStore(t))
+}
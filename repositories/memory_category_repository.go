@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+type MemoryCategoryRepository struct {
+	*memoryStore
+}
+
+// NewMemoryCategoryRepository shares the store of products so the category
+// references and restrictions behave like the foreign keys in PostgreSQL.
+func NewMemoryCategoryRepository(products *MemoryProductRepository) *MemoryCategoryRepository {
+	return &MemoryCategoryRepository{memoryStore: products.memoryStore}
+}
+
+func (r *MemoryCategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range r.categories {
+		categories = append(categories, category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Name == categories[j].Name {
+			return categories[i].ID < categories[j].ID
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (r *MemoryCategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, apperrors.NotFound("categoria com ID %d não encontrada", id)
+	}
+
+	return &category, nil
+}
+
+func (r *MemoryCategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, category := range r.categories {
+		if category.Slug == slug {
+			return &category, nil
+		}
+	}
+
+	return nil, apperrors.NotFound("categoria %s não encontrada", slug)
+}
+
+func (r *MemoryCategoryRepository) Create(ctx context.Context, req models.CategoryRequest) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkCategory(0, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	category := models.Category{
+		ID:        r.nextCategoryID,
+		Slug:      req.Slug,
+		Name:      req.Name,
+		ParentID:  copyID(req.ParentID),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	r.categories[category.ID] = category
+	r.nextCategoryID++
+
+	return &category, nil
+}
+
+func (r *MemoryCategoryRepository) Update(ctx context.Context, id int, req models.CategoryRequest) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, apperrors.NotFound("categoria com ID %d não encontrada", id)
+	}
+
+	if err := r.checkCategory(id, req); err != nil {
+		return nil, err
+	}
+
+	category.Slug = req.Slug
+	category.Name = req.Name
+	category.ParentID = copyID(req.ParentID)
+	category.UpdatedAt = time.Now()
+	r.categories[id] = category
+
+	// Products embed the category summary, which the SQL implementation reads on every query.
+	for productID, product := range r.products {
+		if product.CategoryID != nil && *product.CategoryID == id {
+			product.Category = r.categorySummary(product.CategoryID)
+			r.products[productID] = product
+		}
+	}
+
+	return &category, nil
+}
+
+func (r *MemoryCategoryRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.categories[id]; !ok {
+		return apperrors.NotFound("categoria com ID %d não encontrada", id)
+	}
+
+	for _, category := range r.categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			return apperrors.Conflict("categoria com ID %d possui subcategorias", id)
+		}
+	}
+	for _, product := range r.products {
+		if product.CategoryID != nil && *product.CategoryID == id {
+			return apperrors.Conflict("categoria com ID %d possui produtos", id)
+		}
+	}
+
+	delete(r.categories, id)
+	return nil
+}
+
+// checkCategory mirrors the unique slug and the parent checks of the SQL
+// implementation. It must be called with the write lock held.
+func (r *MemoryCategoryRepository) checkCategory(id int, req models.CategoryRequest) error {
+	for _, category := range r.categories {
+		if category.ID != id && category.Slug == req.Slug {
+			return apperrors.Conflict("já existe uma categoria com o slug %s", req.Slug)
+		}
+	}
+
+	if req.ParentID == nil {
+		return nil
+	}
+	if _, ok := r.categories[*req.ParentID]; !ok {
+		return apperrors.Validation("categoria pai com ID %d não encontrada", *req.ParentID)
+	}
+
+	for ancestor := req.ParentID; ancestor != nil; ancestor = r.categories[*ancestor].ParentID {
+		if *ancestor == id {
+			return apperrors.Validation("a categoria %d não pode ficar abaixo de si mesma ou de uma subcategoria", id)
+		}
+	}
+	return nil
+}
+
+func copyID(id *int) *int {
+	if id == nil {
+		return nil
+	}
+	value := *id
+	return &value
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+func (r *MemoryProductRepository) Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.ProductFacets, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	facets := &models.ProductFacets{}
+	if request.Category {
+		facets.Categories = r.categoryFacet(filter.WithoutCategory())
+	}
+	if request.Price {
+		facets.PriceRanges = r.priceFacet(filter.WithoutPrice(), request.PriceBuckets)
+	}
+	if request.Stock {
+		facets.StockStatus = r.stockFacet(filter.WithoutStock())
+	}
+
+	return facets, nil
+}
+
+// matching returns the products that are not in the trash and match filter.
+// It must be called with the lock held.
+func (r *MemoryProductRepository) matching(filter models.ProductFilter) []models.Product {
+	var matched []models.Product
+	for _, product := range r.products {
+		if product.DeletedAt == nil && matchesFilter(product, filter) {
+			matched = append(matched, product)
+		}
+	}
+	return matched
+}
+
+func (r *MemoryProductRepository) categoryFacet(filter models.ProductFilter) []models.CategoryFacet {
+	counts := make(map[int]int)
+	for _, product := range r.matching(filter) {
+		if product.CategoryID != nil {
+			counts[*product.CategoryID]++
+		}
+	}
+
+	categories := []models.CategoryFacet{}
+	for id, count := range counts {
+		category := r.categories[id]
+		categories = append(categories, models.CategoryFacet{
+			CategorySummary: models.CategorySummary{ID: category.ID, Slug: category.Slug, Name: category.Name},
+			Count:           count,
+		})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		switch {
+		case a.Count != b.Count:
+			return a.Count > b.Count
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	return categories
+}
+
+func (r *MemoryProductRepository) priceFacet(filter models.ProductFilter, boundaries []money.Money) []models.PriceRangeFacet {
+	ranges := models.PriceRanges(boundaries)
+	for _, product := range r.matching(filter) {
+		price := product.Prices[filter.Currency]
+		bucket := sort.Search(len(boundaries), func(i int) bool { return price.Cmp(boundaries[i]) < 0 })
+		ranges[bucket].Count++
+	}
+	return ranges
+}
+
+func (r *MemoryProductRepository) stockFacet(filter models.ProductFilter) []models.StockStatusFacet {
+	inStock := models.StockStatusFacet{Status: models.StockStatusInStock}
+	low := models.StockStatusFacet{Status: models.StockStatusLow}
+	outOfStock := models.StockStatusFacet{Status: models.StockStatusOutOfStock}
+	for _, product := range r.matching(filter) {
+		switch product.StockStatus {
+		case models.StockStatusLow:
+			low.Count++
+		case models.StockStatusOutOfStock:
+			outOfStock.Count++
+		default:
+			inStock.Count++
+		}
+	}
+	return []models.StockStatusFacet{inStock, low, outOfStock}
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *MemoryProductRepository) AddImage(ctx context.Context, productID int, image models.ProductImage, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	image.ID = r.nextImageID
+	image.URL = ""
+	image.Position = len(existing.Images) + 1
+	image.Primary = image.Primary || len(existing.Images) == 0
+
+	images := make([]models.ProductImage, 0, len(existing.Images)+1)
+	for _, other := range existing.Images {
+		if image.Primary {
+			other.Primary = false
+		}
+		images = append(images, other)
+	}
+	existing.Images = append(images, image)
+
+	product, err := r.saveProduct(ctx, before, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	r.nextImageID++
+	return product, nil
+}
+
+func (r *MemoryProductRepository) SetPrimaryImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	if imageIndex(existing.Images, imageID) < 0 {
+		return nil, apperrors.NotFound("imagem com ID %d não encontrada no produto %d", imageID, productID)
+	}
+
+	images := make([]models.ProductImage, len(existing.Images))
+	for i, image := range existing.Images {
+		image.Primary = image.ID == imageID
+		images[i] = image
+	}
+	existing.Images = images
+
+	return r.saveProduct(ctx, before, existing)
+}
+
+func (r *MemoryProductRepository) ReorderImages(ctx context.Context, productID int, imageIDs []int, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	current := make([]int, len(existing.Images))
+	for i, image := range existing.Images {
+		current[i] = image.ID
+	}
+	if err := checkImageOrder(current, imageIDs); err != nil {
+		return nil, err
+	}
+
+	images := make([]models.ProductImage, len(imageIDs))
+	for i, id := range imageIDs {
+		image := existing.Images[imageIndex(existing.Images, id)]
+		image.Position = i + 1
+		images[i] = image
+	}
+	existing.Images = images
+
+	return r.saveProduct(ctx, before, existing)
+}
+
+func (r *MemoryProductRepository) DeleteImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, *models.ProductImage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	before := existing
+
+	index := imageIndex(existing.Images, imageID)
+	if index < 0 {
+		return nil, nil, apperrors.NotFound("imagem com ID %d não encontrada no produto %d", imageID, productID)
+	}
+	removed := existing.Images[index]
+
+	images := make([]models.ProductImage, 0, len(existing.Images)-1)
+	for i, image := range existing.Images {
+		if i == index {
+			continue
+		}
+		image.Position = len(images) + 1
+		image.Primary = image.Primary || (removed.Primary && image.Position == 1)
+		images = append(images, image)
+	}
+	existing.Images = images
+
+	product, err := r.saveProduct(ctx, before, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, &removed, nil
+}
+
+func imageIndex(images []models.ProductImage, id int) int {
+	for i, image := range images {
+		if image.ID == id {
+			return i
+		}
+	}
+	return -1
+}
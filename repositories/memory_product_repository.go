@@ -0,0 +1,676 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/gtin"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+type MemoryProductRepository struct {
+	*memoryStore
+}
+
+func NewMemoryProductRepository() *MemoryProductRepository {
+	return &MemoryProductRepository{memoryStore: newMemoryStore()}
+}
+
+func (r *MemoryProductRepository) SeedInitialData() error {
+	r.mu.RLock()
+	count := len(r.products)
+	r.mu.RUnlock()
+
+	if count > 0 {
+		return nil
+	}
+
+	// Seed prices are in BRL, like the ones created by the SQL migrations.
+	brl := func(amount string) map[string]money.Money {
+		return map[string]money.Money{"BRL": money.MustParse(amount)}
+	}
+
+	r.mu.Lock()
+	category := func(slug, name string) *int {
+		now := time.Now()
+		id := r.nextCategoryID
+		r.categories[id] = models.Category{ID: id, Slug: slug, Name: name, CreatedAt: now, UpdatedAt: now}
+		r.nextCategoryID++
+		return &id
+	}
+	eletronicos := category("eletronicos", "Eletrônicos")
+	computadores := category("computadores", "Computadores")
+	audio := category("audio", "Áudio")
+	acessorios := category("acessorios", "Acessórios")
+	r.mu.Unlock()
+
+	seed := []models.CreateProductRequest{
+		{Name: "Smartphone Samsung Galaxy S23", Description: "Smartphone Android com 256GB de armazenamento", Prices: brl("2999.99"), CategoryID: eletronicos, StockQuantity: 50},
+		{Name: "Notebook Dell XPS 13", Description: "Laptop ultrafino com processador Intel i7", Prices: brl("4999.99"), CategoryID: computadores, StockQuantity: 25},
+		{Name: "Fone de Ouvido Sony WH-1000XM4", Description: "Fone com cancelamento de ruído ativo", Prices: brl("799.99"), CategoryID: audio, StockQuantity: 100},
+		{Name: "Tablet Apple iPad Air", Description: "Tablet com tela de 10.9 polegadas", Prices: brl("2499.99"), CategoryID: eletronicos, StockQuantity: 30},
+		{Name: "Teclado Mecânico Logitech MX Keys", Description: "Teclado sem fio para produtividade", Prices: brl("349.99"), CategoryID: acessorios, StockQuantity: 75},
+	}
+
+	for _, req := range seed {
+		if _, err := r.Create(context.Background(), req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryProductRepository) GetByID(ctx context.Context, id int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	product, ok := r.products[id]
+	if !ok || product.DeletedAt != nil {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", id)
+	}
+
+	return &product, nil
+}
+
+func (r *MemoryProductRepository) Create(ctx context.Context, req models.CreateProductRequest) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkIdentifiers(0, req.SKU, req.GTIN); err != nil {
+		return nil, err
+	}
+	categoryID, err := r.categoryRef(req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	product := models.Product{
+		ID:              r.nextID,
+		Name:            req.Name,
+		Description:     req.Description,
+		SKU:             req.SKU,
+		GTIN:            req.GTIN,
+		Prices:          clonePrices(req.Prices),
+		CategoryID:      categoryID,
+		Category:        r.categorySummary(categoryID),
+		Tags:            []string{},
+		Images:          []models.ProductImage{},
+		StockQuantity:   req.StockQuantity,
+		ReorderPoint:    req.ReorderPoint,
+		ReorderQuantity: req.ReorderQuantity,
+		Version:         1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	product.SummarizeVariants()
+
+	if err := r.recordHistory(ctx, product.ID, models.HistoryActionCreate, nil, &product); err != nil {
+		return nil, err
+	}
+	r.recordStockChange(ctx, product.ID, nil, 0, product.StockQuantity, models.StockReasonInitial)
+
+	r.products[product.ID] = product
+	r.nextID++
+
+	return &product, nil
+}
+
+func (r *MemoryProductRepository) Update(ctx context.Context, id int, req models.UpdateProductRequest, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(id, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	if req.Name != nil {
+		existing.Name = *req.Name
+	}
+	if req.Description != nil {
+		existing.Description = *req.Description
+	}
+	if req.SKU != nil {
+		existing.SKU = *req.SKU
+	}
+	if req.GTIN != nil {
+		existing.GTIN = *req.GTIN
+	}
+	if req.Prices != nil {
+		existing.Prices = clonePrices(req.Prices)
+	}
+	if req.CategoryID != nil {
+		existing.CategoryID, err = r.categoryRef(req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		existing.Category = r.categorySummary(existing.CategoryID)
+	}
+	if req.StockQuantity != nil {
+		if err := belowReserved(*req.StockQuantity, existing.ReservedQuantity); err != nil {
+			return nil, err
+		}
+		existing.StockQuantity = *req.StockQuantity
+		existing.SummarizeVariants()
+	}
+	if req.ReorderPoint != nil {
+		existing.ReorderPoint = *req.ReorderPoint
+		existing.SummarizeVariants()
+	}
+	if req.ReorderQuantity != nil {
+		existing.ReorderQuantity = *req.ReorderQuantity
+	}
+	if err := r.checkIdentifiers(id, existing.SKU, existing.GTIN); err != nil {
+		return nil, err
+	}
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+
+	if err := r.recordHistory(ctx, id, models.HistoryActionUpdate, &before, &existing); err != nil {
+		return nil, err
+	}
+	r.recordStockChange(ctx, id, nil, before.StockQuantity, existing.StockQuantity, models.StockReasonCorrection)
+
+	r.products[id] = existing
+
+	return &existing, nil
+}
+
+func (r *MemoryProductRepository) Delete(ctx context.Context, id int, expectedVersion *int) error {
+	if err := ctx.Err(); err != nil {
+		return translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(id, expectedVersion, false)
+	if err != nil {
+		return err
+	}
+	before := existing
+
+	now := time.Now()
+	existing.DeletedAt = &now
+	existing.Version++
+	existing.UpdatedAt = now
+
+	if err := r.recordHistory(ctx, id, models.HistoryActionDelete, &before, &existing); err != nil {
+		return err
+	}
+
+	r.products[id] = existing
+
+	log.Printf("Produto com ID %d movido para a lixeira", id)
+	return nil
+}
+
+func (r *MemoryProductRepository) GetDeleted(ctx context.Context) ([]models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var products []models.Product
+	for _, product := range r.products {
+		if product.DeletedAt != nil {
+			products = append(products, product)
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].DeletedAt.After(*products[j].DeletedAt)
+	})
+	return products, nil
+}
+
+func (r *MemoryProductRepository) Restore(ctx context.Context, id int, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(id, expectedVersion, true)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	existing.DeletedAt = nil
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+
+	if err := r.recordHistory(ctx, id, models.HistoryActionRestore, &before, &existing); err != nil {
+		return nil, err
+	}
+
+	r.products[id] = existing
+
+	log.Printf("Produto com ID %d restaurado da lixeira", id)
+	return &existing, nil
+}
+
+func (r *MemoryProductRepository) AddTags(ctx context.Context, id int, tags []string, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(id, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	merged := append([]string{}, existing.Tags...)
+	for _, tag := range tags {
+		if !containsTag(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	sort.Strings(merged)
+
+	existing.Tags = merged
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+
+	if err := r.recordHistory(ctx, id, models.HistoryActionUpdate, &before, &existing); err != nil {
+		return nil, err
+	}
+
+	r.products[id] = existing
+
+	return &existing, nil
+}
+
+func (r *MemoryProductRepository) RemoveTag(ctx context.Context, id int, tag string, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(id, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	if !containsTag(existing.Tags, tag) {
+		return nil, apperrors.NotFound("produto com ID %d não possui a tag %s", id, tag)
+	}
+
+	remaining := make([]string, 0, len(existing.Tags)-1)
+	for _, t := range existing.Tags {
+		if t != tag {
+			remaining = append(remaining, t)
+		}
+	}
+
+	existing.Tags = remaining
+	existing.Version++
+	existing.UpdatedAt = time.Now()
+
+	if err := r.recordHistory(ctx, id, models.HistoryActionUpdate, &before, &existing); err != nil {
+		return nil, err
+	}
+
+	r.products[id] = existing
+
+	return &existing, nil
+}
+
+func (r *MemoryProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged []models.Product
+	for id, product := range r.products {
+		if product.DeletedAt != nil && product.DeletedAt.Before(deletedBefore) {
+			product := product
+			if err := r.recordHistory(ctx, id, models.HistoryActionPurge, &product, nil); err != nil {
+				return purged, err
+			}
+			delete(r.products, id)
+			r.deleteReservations(func(reservation models.StockReservation) bool {
+				return reservation.ProductID == id
+			})
+			delete(r.alertStatuses, id)
+			purged = append(purged, product)
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *MemoryProductRepository) GetHistory(ctx context.Context, productID int, nextToken models.NextTokenRequest) ([]models.ProductHistory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Purged products are gone from r.products but their history is kept.
+	_, known := r.products[productID]
+	var history []models.ProductHistory
+	for _, entry := range r.history {
+		if entry.ProductID != productID {
+			continue
+		}
+		known = true
+		if nextToken.Row > 0 {
+			if nextToken.Order == "asc" && entry.ID <= int64(nextToken.Row) {
+				continue
+			}
+			if nextToken.Order != "asc" && entry.ID >= int64(nextToken.Row) {
+				continue
+			}
+		}
+		changes, err := presentChanges(entry.Changes)
+		if err != nil {
+			return nil, err
+		}
+		entry.Changes = changes
+		history = append(history, entry)
+	}
+	if !known {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", productID)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		if nextToken.Order == "asc" {
+			return history[i].ID < history[j].ID
+		}
+		return history[i].ID > history[j].ID
+	})
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	if len(history) > limit+1 {
+		history = history[:limit+1]
+	}
+
+	return history, nil
+}
+
+// recordHistory must be called with the write lock held.
+func (r *MemoryProductRepository) recordHistory(ctx context.Context, productID int, action string, before, after *models.Product) error {
+	entry, err := newHistoryEntry(ctx, productID, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	// Snapshot the products so later changes to the caller's values don't leak into the history.
+	if before != nil {
+		snapshot := *before
+		entry.Before = &snapshot
+	}
+	if after != nil {
+		snapshot := *after
+		entry.After = &snapshot
+	}
+
+	entry.ID = r.nextHistoryID
+	r.nextHistoryID++
+	r.history = append(r.history, entry)
+	return nil
+}
+
+// lookup returns the product targeted by a guarded write, applying the same
+// checks as mutate in the SQL implementation and failing with stateConflict.
+func (r *MemoryProductRepository) lookup(id int, expectedVersion *int, expectDeleted bool) (models.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return product, apperrors.NotFound("produto com ID %d não encontrado", id)
+	}
+
+	deleted := product.DeletedAt != nil
+	if deleted != expectDeleted || (expectedVersion != nil && *expectedVersion != product.Version) {
+		return product, stateConflict(id, product.Version, deleted, expectDeleted)
+	}
+
+	return product, nil
+}
+
+// checkIdentifiers mirrors the unique indexes on sku and gtin, which also
+// cover products in the trash. It must be called with the lock held.
+func (r *MemoryProductRepository) checkIdentifiers(id int, sku, code string) error {
+	for _, product := range r.products {
+		if product.ID == id {
+			continue
+		}
+		if sku != "" && product.SKU == sku {
+			return apperrors.Conflict("já existe um produto com o SKU %s", sku)
+		}
+		if code != "" && product.GTIN != "" && gtin.Normalize(product.GTIN) == gtin.Normalize(code) {
+			return apperrors.Conflict("já existe um produto com o GTIN %s", code)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return r.findOne(ctx, func(product models.Product) bool {
+		return product.SKU == sku
+	}, "produto com SKU %s não encontrado", sku)
+}
+
+func (r *MemoryProductRepository) GetByGTIN(ctx context.Context, code string) (*models.Product, error) {
+	return r.findOne(ctx, func(product models.Product) bool {
+		return product.GTIN != "" && gtin.Normalize(product.GTIN) == gtin.Normalize(code)
+	}, "produto com código de barras %s não encontrado", code)
+}
+
+func (r *MemoryProductRepository) findOne(ctx context.Context, match func(models.Product) bool, notFound string, args ...interface{}) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, product := range r.products {
+		if product.DeletedAt == nil && match(product) {
+			return &product, nil
+		}
+	}
+
+	return nil, apperrors.NotFound(notFound, args...)
+}
+
+// FindByFilter has no planner statistics, so the estimated count mode
+// returns the exact count.
+func (r *MemoryProductRepository) FindByFilter(ctx context.Context, filter models.ProductFilter, nextToken models.NextTokenRequest, count models.CountMode) ([]models.Product, *models.Total, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := nextToken.Keys()
+
+	var matched []models.Product
+	filtered := 0
+	for _, product := range r.products {
+		if product.DeletedAt != nil || !matchesFilter(product, filter) {
+			continue
+		}
+		filtered++
+
+		if keys[0].After != nil && compareToCursor(product, keys, filter.Currency) <= 0 {
+			continue
+		}
+
+		matched = append(matched, product)
+	}
+
+	// The count is always exact, so CountEstimated never sets Estimated.
+	var total *models.Total
+	if count != models.CountNone {
+		total = &models.Total{Count: filtered, Estimated: false}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compareSortValues(sortFieldValue(matched[i], key.Field, filter.Currency), sortFieldValue(matched[j], key.Field, filter.Currency))
+			if cmp != 0 {
+				return (cmp < 0) != key.Desc
+			}
+		}
+		return false
+	})
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	if nextToken.Offset >= len(matched) {
+		matched = nil
+	} else {
+		matched = matched[nextToken.Offset:]
+	}
+
+	if len(matched) > limit+1 {
+		matched = matched[:limit+1]
+	}
+
+	return matched, total, nil
+}
+
+func matchesFilter(product models.Product, filter models.ProductFilter) bool {
+	if filter.Name != "" && !strings.Contains(strings.ToLower(product.Name), strings.ToLower(filter.Name)) {
+		return false
+	}
+	if filter.CategoryIDs != nil && !inCategories(product, filter.CategoryIDs) {
+		return false
+	}
+	if len(filter.TagsAny) > 0 {
+		found := false
+		for _, tag := range filter.TagsAny {
+			if containsTag(product.Tags, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, tag := range filter.TagsAll {
+		if !containsTag(product.Tags, tag) {
+			return false
+		}
+	}
+	price, ok := product.Prices[filter.Currency]
+	if !ok {
+		return false
+	}
+	if filter.MinPrice != nil && price.Cmp(*filter.MinPrice) < 0 {
+		return false
+	}
+	if filter.MaxPrice != nil && price.Cmp(*filter.MaxPrice) > 0 {
+		return false
+	}
+	if len(filter.Options) > 0 {
+		found := false
+		for _, variant := range product.Variants {
+			if variant.MatchesOptions(filter.Options) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.MinStock != nil && product.TotalStock < *filter.MinStock {
+		return false
+	}
+	if filter.MaxStock != nil && product.TotalStock > *filter.MaxStock {
+		return false
+	}
+	if filter.StockStatus != "" && product.StockStatus != filter.StockStatus {
+		return false
+	}
+	return true
+}
+
+// categoryRef mirrors the foreign key on products.category_id: it returns a
+// copy of id, nil for no category (nil or 0), or an error when the category
+// does not exist. It must be called with the lock held.
+func (r *MemoryProductRepository) categoryRef(id *int) (*int, error) {
+	if id == nil || *id == 0 {
+		return nil, nil
+	}
+	if _, ok := r.categories[*id]; !ok {
+		return nil, apperrors.Validation("categoria com ID %d não encontrada", *id)
+	}
+	ref := *id
+	return &ref, nil
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func inCategories(product models.Product, categoryIDs []int) bool {
+	if product.CategoryID == nil {
+		return false
+	}
+	for _, id := range categoryIDs {
+		if *product.CategoryID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// clonePrices copies the price map so stored products never share it with callers.
+func clonePrices(prices map[string]money.Money) map[string]money.Money {
+	cloned := make(map[string]money.Money, len(prices))
+	for currency, amount := range prices {
+		cloned[currency] = amount
+	}
+	return cloned
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+func TestMemoryFindByFilterCountsExactly(t *testing.T) {
+	repo := NewMemoryProductRepository()
+	if err := repo.SeedInitialData(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, count := range []models.CountMode{models.CountExact, models.CountEstimated} {
+		_, total, err := repo.FindByFilter(context.Background(), models.ProductFilter{Currency: "BRL"}, models.NextTokenRequest{Limit: 2}, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total == nil || total.Estimated || total.Count != len(repo.products) {
+			t.Errorf("%s: total = %+v, want exact count %d", count, total, len(repo.products))
+		}
+	}
+
+	_, total, err := repo.FindByFilter(context.Background(), models.ProductFilter{Currency: "BRL"}, models.NextTokenRequest{Limit: 2}, models.CountNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != nil {
+		t.Errorf("none: total = %+v, want nil", total)
+	}
+}
+
+func TestMemoryGTINFormsCollide(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryProductRepository()
+	prices := map[string]money.Money{"BRL": money.MustParse("10.00")}
+
+	created, err := repo.Create(ctx, models.CreateProductRequest{Name: "Refrigerante", GTIN: "036000291452", Prices: prices})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []string{"0036000291452", "00036000291452"} {
+		_, err := repo.Create(ctx, models.CreateProductRequest{Name: "Duplicado", GTIN: code, Prices: prices})
+		if !errors.Is(err, apperrors.ErrConflict) {
+			t.Errorf("Create with GTIN %s error = %v, want conflict", code, err)
+		}
+
+		found, err := repo.GetByGTIN(ctx, code)
+		if err != nil || found.ID != created.ID {
+			t.Errorf("GetByGTIN(%s) = %v, %v; want product %d", code, found, err, created.ID)
+		}
+	}
+}
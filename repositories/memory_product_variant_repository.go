@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *MemoryProductRepository) CreateVariant(ctx context.Context, productID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := models.ProductVariant{ID: r.nextVariantID}
+	variants := append(append([]models.ProductVariant{}, existing.Variants...), variant)
+	product, err := r.writeVariants(ctx, existing, variants, len(variants)-1, req)
+	if err != nil {
+		return nil, err
+	}
+	r.recordStockChange(ctx, productID, &variant.ID, 0, req.StockQuantity, models.StockReasonInitial)
+
+	r.nextVariantID++
+	return product, nil
+}
+
+func (r *MemoryProductRepository) UpdateVariant(ctx context.Context, productID, variantID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+
+	index := variantIndex(existing.Variants, variantID)
+	if index < 0 {
+		return nil, apperrors.NotFound("variante com ID %d não encontrada no produto %d", variantID, productID)
+	}
+
+	variants := append([]models.ProductVariant{}, existing.Variants...)
+	product, err := r.writeVariants(ctx, existing, variants, index, req)
+	if err != nil {
+		return nil, err
+	}
+	r.recordStockChange(ctx, productID, &variantID, existing.Variants[index].StockQuantity, req.StockQuantity, models.StockReasonCorrection)
+
+	return product, nil
+}
+
+func (r *MemoryProductRepository) DeleteVariant(ctx context.Context, productID, variantID int, expectedVersion *int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, err
+	}
+	before := existing
+
+	index := variantIndex(existing.Variants, variantID)
+	if index < 0 {
+		return nil, apperrors.NotFound("variante com ID %d não encontrada no produto %d", variantID, productID)
+	}
+	if reserved := existing.Variants[index].ReservedQuantity; reserved > 0 {
+		return nil, apperrors.Conflict("a variante %d tem %d unidades reservadas", variantID, reserved)
+	}
+
+	variants := append([]models.ProductVariant{}, existing.Variants[:index]...)
+	existing.Variants = append(variants, existing.Variants[index+1:]...)
+	product, err := r.saveProduct(ctx, before, existing)
+	if err != nil {
+		return nil, err
+	}
+
+	r.deleteReservations(func(reservation models.StockReservation) bool {
+		return reservation.VariantID != nil && *reservation.VariantID == variantID
+	})
+	return product, nil
+}
+
+// writeVariants applies req to variants[index], checks it against the other
+// variants like the SQL constraints do and stores the product with the new
+// variants. It must be called with the write lock held.
+func (r *MemoryProductRepository) writeVariants(ctx context.Context, existing models.Product, variants []models.ProductVariant, index int, req models.VariantRequest) (*models.Product, error) {
+	before := existing
+	variant := variants[index]
+	if err := belowReserved(req.StockQuantity, variant.ReservedQuantity); err != nil {
+		return nil, err
+	}
+
+	for i, other := range variants {
+		if i == index {
+			continue
+		}
+		if err := sameOptionNames(other.Options, req.Options); err != nil {
+			return nil, err
+		}
+		if len(other.Options) == len(req.Options) && other.MatchesOptions(req.Options) {
+			return nil, apperrors.Conflict("já existe uma variante com essas opções")
+		}
+	}
+	if req.SKU != "" {
+		for _, product := range r.products {
+			for _, other := range product.Variants {
+				if other.ID != variant.ID && other.SKU == req.SKU {
+					return nil, apperrors.Conflict("já existe uma variante com este SKU")
+				}
+			}
+		}
+	}
+
+	options := make(map[string]string, len(req.Options))
+	for name, value := range req.Options {
+		options[name] = value
+	}
+
+	variant.SKU = req.SKU
+	variant.Options = options
+	variant.Prices = nil
+	if len(req.Prices) > 0 {
+		variant.Prices = clonePrices(req.Prices)
+	}
+	variant.StockQuantity = req.StockQuantity
+	variants[index] = variant
+
+	existing.Variants = variants
+	return r.saveProduct(ctx, before, existing)
+}
+
+// saveProduct stores a product whose variants or images changed, bumping its
+// version. It must be called with the write lock held.
+func (r *MemoryProductRepository) saveProduct(ctx context.Context, before, product models.Product) (*models.Product, error) {
+	product.SummarizeVariants()
+	product.Version++
+	product.UpdatedAt = time.Now()
+
+	if err := r.recordHistory(ctx, product.ID, models.HistoryActionUpdate, &before, &product); err != nil {
+		return nil, err
+	}
+
+	r.products[product.ID] = product
+
+	return &product, nil
+}
+
+func variantIndex(variants []models.ProductVariant, id int) int {
+	for i, variant := range variants {
+		if variant.ID == id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/audit"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *MemoryProductRepository) ReserveStock(ctx context.Context, productID int, req models.ReservationRequest, expiresAt time.Time) (*models.StockReservation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	product, ok := r.products[productID]
+	if !ok || product.DeletedAt != nil {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", productID)
+	}
+
+	available := product.StockQuantity - product.ReservedQuantity
+	if req.VariantID == nil {
+		if len(product.Variants) > 0 {
+			return nil, apperrors.Validation("o produto %d tem variantes; informe variant_id", productID)
+		}
+	} else {
+		index := variantIndex(product.Variants, *req.VariantID)
+		if index < 0 {
+			return nil, apperrors.NotFound("variante com ID %d não encontrada no produto %d", *req.VariantID, productID)
+		}
+		available = product.Variants[index].StockQuantity - product.Variants[index].ReservedQuantity
+	}
+	if available < req.Quantity {
+		return nil, insufficientStock(available)
+	}
+
+	now := time.Now()
+	reservation := models.StockReservation{
+		ID:        r.nextReserveID,
+		ProductID: productID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+		Status:    models.ReservationActive,
+		Reference: req.Reference,
+		Actor:     audit.ActorFromContext(ctx),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.adjustReserved(reservation, reservation.Quantity)
+	r.reservations[reservation.ID] = reservation
+	r.nextReserveID++
+
+	return &reservation, nil
+}
+
+func (r *MemoryProductRepository) GetReservation(ctx context.Context, id int64) (*models.StockReservation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, apperrors.NotFound("reserva com ID %d não encontrada", id)
+	}
+
+	return &reservation, nil
+}
+
+func (r *MemoryProductRepository) ConfirmReservation(ctx context.Context, id int64) (*models.StockReservation, *models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, nil, apperrors.NotFound("reserva com ID %d não encontrada", id)
+	}
+
+	now := time.Now()
+	if reservation.Status != models.ReservationActive || !reservation.ExpiresAt.After(now) {
+		return nil, nil, reservationClosed(reservation, now)
+	}
+
+	existing, err := r.lookup(reservation.ProductID, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	before := existing
+
+	balance := existing.StockQuantity - reservation.Quantity
+	if reservation.VariantID == nil {
+		existing.StockQuantity = balance
+		existing.ReservedQuantity -= reservation.Quantity
+	} else {
+		existing.Variants = append([]models.ProductVariant{}, existing.Variants...)
+		index := variantIndex(existing.Variants, *reservation.VariantID)
+		balance = existing.Variants[index].StockQuantity - reservation.Quantity
+		existing.Variants[index].StockQuantity = balance
+		existing.Variants[index].ReservedQuantity -= reservation.Quantity
+	}
+
+	product, err := r.saveProduct(ctx, before, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reference := reservation.Reference
+	if reference == "" {
+		reference = fmt.Sprintf("reserva %d", reservation.ID)
+	}
+	r.appendStockMovement(ctx, models.StockMovement{
+		ProductID: reservation.ProductID,
+		VariantID: reservation.VariantID,
+		Quantity:  -reservation.Quantity,
+		Balance:   balance,
+		Reason:    models.StockReasonSale,
+		Reference: reference,
+	})
+
+	reservation.Status = models.ReservationConfirmed
+	reservation.UpdatedAt = now
+	r.reservations[id] = reservation
+
+	return &reservation, product, nil
+}
+
+func (r *MemoryProductRepository) CancelReservation(ctx context.Context, id int64) (*models.StockReservation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, apperrors.NotFound("reserva com ID %d não encontrada", id)
+	}
+
+	now := time.Now()
+	if reservation.Status != models.ReservationActive {
+		return nil, reservationClosed(reservation, now)
+	}
+
+	r.adjustReserved(reservation, -reservation.Quantity)
+	reservation.Status = models.ReservationCancelled
+	reservation.UpdatedAt = now
+	r.reservations[id] = reservation
+
+	return &reservation, nil
+}
+
+func (r *MemoryProductRepository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := 0
+	for id, reservation := range r.reservations {
+		if reservation.Status != models.ReservationActive || reservation.ExpiresAt.After(now) {
+			continue
+		}
+
+		r.adjustReserved(reservation, -reservation.Quantity)
+		reservation.Status = models.ReservationExpired
+		reservation.UpdatedAt = now
+		r.reservations[id] = reservation
+		expired++
+	}
+
+	return expired, nil
+}
+
+// adjustReserved changes the reserved quantity held by reservation without
+// bumping the product version, like the SQL implementation. It must be called
+// with the write lock held.
+func (r *MemoryProductRepository) adjustReserved(reservation models.StockReservation, delta int) {
+	product, ok := r.products[reservation.ProductID]
+	if !ok {
+		return
+	}
+
+	if reservation.VariantID == nil {
+		product.ReservedQuantity += delta
+	} else {
+		product.Variants = append([]models.ProductVariant{}, product.Variants...)
+		if index := variantIndex(product.Variants, *reservation.VariantID); index >= 0 {
+			product.Variants[index].ReservedQuantity += delta
+		}
+	}
+	product.SummarizeVariants()
+
+	r.products[product.ID] = product
+}
+
+// deleteReservations mirrors the ON DELETE CASCADE of stock_reservations. It
+// must be called with the write lock held.
+func (r *MemoryProductRepository) deleteReservations(match func(models.StockReservation) bool) {
+	for id, reservation := range r.reservations {
+		if match(reservation) {
+			delete(r.reservations, id)
+		}
+	}
+}
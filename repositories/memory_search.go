@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The in-memory search approximates the PostgreSQL one: words are unaccented,
+// lowercased and reduced by a simple plural stemmer, every query word must
+// match, and name, category and description weigh like the A, B and C weights
+// of ts_rank.
+var (
+	searchWord    = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	searchWeights = struct{ name, category, description float32 }{1.0, 0.4, 0.2}
+	stopWords     = map[string]bool{
+		"a": true, "o": true, "as": true, "os": true, "e": true, "de": true, "da": true, "do": true,
+		"das": true, "dos": true, "em": true, "no": true, "na": true, "com": true, "para": true,
+		"por": true, "um": true, "uma": true, "ou": true,
+	}
+)
+
+// foldText removes accents and lowercases text, like unaccent(lower(text)).
+func foldText(text string) string {
+	unaccented, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		unaccented = text
+	}
+	return strings.ToLower(unaccented)
+}
+
+func searchStem(word string) string {
+	stem := foldText(word)
+	switch {
+	case len(stem) > 4 && strings.HasSuffix(stem, "oes"):
+		return strings.TrimSuffix(stem, "oes") + "ao"
+	case len(stem) > 4 && strings.HasSuffix(stem, "ais"):
+		return strings.TrimSuffix(stem, "is") + "l"
+	case len(stem) > 3 && strings.HasSuffix(stem, "s"):
+		return strings.TrimSuffix(stem, "s")
+	}
+	return stem
+}
+
+func searchTerms(query string) []string {
+	var terms []string
+	for _, word := range searchWord.FindAllString(query, -1) {
+		if !stopWords[strings.ToLower(word)] {
+			terms = append(terms, searchStem(word))
+		}
+	}
+	return terms
+}
+
+// searchMatches counts the words of text matching terms and returns text with
+// those words wrapped in <mark> tags.
+func searchMatches(text string, terms map[string]bool) (int, string) {
+	count := 0
+	highlighted := searchWord.ReplaceAllStringFunc(text, func(word string) string {
+		if terms[searchStem(word)] {
+			count++
+			return "<mark>" + word + "</mark>"
+		}
+		return word
+	})
+	return count, highlighted
+}
+
+func (r *MemoryProductRepository) Search(ctx context.Context, search models.ProductSearch, nextToken models.SearchNextToken) ([]models.ProductSearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	terms := searchTerms(search.Query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var results []models.ProductSearchResult
+	for _, product := range r.products {
+		if _, ok := product.Prices[search.Currency]; product.DeletedAt != nil || !ok {
+			continue
+		}
+
+		category := ""
+		if product.Category != nil {
+			category = product.Category.Name
+		}
+		text := strings.Join([]string{product.Name, category, product.Description}, " ")
+
+		found := make(map[string]bool)
+		for _, word := range searchWord.FindAllString(text, -1) {
+			found[searchStem(word)] = true
+		}
+		matchesAll := true
+		wanted := make(map[string]bool, len(terms))
+		for _, term := range terms {
+			matchesAll = matchesAll && found[term]
+			wanted[term] = true
+		}
+		if !matchesAll {
+			continue
+		}
+
+		nameMatches, name := searchMatches(product.Name, wanted)
+		categoryMatches, _ := searchMatches(category, wanted)
+		descriptionMatches, description := searchMatches(product.Description, wanted)
+
+		result := models.ProductSearchResult{
+			Product: product,
+			Rank: float32(nameMatches)*searchWeights.name +
+				float32(categoryMatches)*searchWeights.category +
+				float32(descriptionMatches)*searchWeights.description,
+			Highlights: models.ProductHighlights{Name: name, Description: description},
+		}
+
+		if nextToken.Row > 0 && (result.Rank > nextToken.Rank || (result.Rank == nextToken.Rank && product.ID >= nextToken.Row)) {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	if len(results) > nextToken.Limit+1 {
+		results = results[:nextToken.Limit+1]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// sortFieldValue returns the value of a sort field with the types used in
+// models.SortKey.After.
+func sortFieldValue(product models.Product, field, currency string) interface{} {
+	switch field {
+	case models.SortName:
+		return product.Name
+	case models.SortPrice:
+		return product.Prices[currency]
+	case models.SortCreatedAt:
+		return product.CreatedAt
+	case models.SortUpdatedAt:
+		return product.UpdatedAt
+	case models.SortStock:
+		return product.TotalStock
+	}
+	return product.ID
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		b := b.(int)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		// Byte order, which for UTF-8 is the code point order of the "C"
+		// collation used by the Postgres store.
+		return strings.Compare(a, b.(string))
+	case money.Money:
+		return a.Cmp(b.(money.Money))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+// compareToCursor tells whether product comes before (-1), at (0) or after (1)
+// the cursor held in the After values of keys, in the order of keys.
+func compareToCursor(product models.Product, keys []models.SortKey, currency string) int {
+	for _, key := range keys {
+		cmp := compareSortValues(sortFieldValue(product, key.Field, currency), key.After)
+		if cmp != 0 {
+			if key.Desc {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return 0
+}
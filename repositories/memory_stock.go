@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/audit"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *MemoryProductRepository) RecordStockMovement(ctx context.Context, productID int, req models.StockMovementRequest, expectedVersion *int) (*models.StockMovement, *models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.lookup(productID, expectedVersion, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	before := existing
+
+	index := -1
+	current, reserved := existing.StockQuantity, existing.ReservedQuantity
+	if req.VariantID == nil {
+		if len(existing.Variants) > 0 {
+			return nil, nil, apperrors.Validation("o produto %d tem variantes; informe variant_id", productID)
+		}
+	} else {
+		index = variantIndex(existing.Variants, *req.VariantID)
+		if index < 0 {
+			return nil, nil, apperrors.NotFound("variante com ID %d não encontrada no produto %d", *req.VariantID, productID)
+		}
+		current, reserved = existing.Variants[index].StockQuantity, existing.Variants[index].ReservedQuantity
+	}
+
+	balance := current + req.Quantity
+	if balance < reserved {
+		return nil, nil, insufficientStock(current - reserved)
+	}
+
+	if index < 0 {
+		existing.StockQuantity = balance
+	} else {
+		existing.Variants = append([]models.ProductVariant{}, existing.Variants...)
+		existing.Variants[index].StockQuantity = balance
+	}
+
+	product, err := r.saveProduct(ctx, before, existing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	movement := r.appendStockMovement(ctx, models.StockMovement{
+		ProductID: productID,
+		VariantID: req.VariantID,
+		Quantity:  req.Quantity,
+		Balance:   balance,
+		Reason:    req.Reason,
+		Reference: req.Reference,
+	})
+	return &movement, product, nil
+}
+
+// recordStockChange must be called with the write lock held.
+func (r *MemoryProductRepository) recordStockChange(ctx context.Context, productID int, variantID *int, before, after int, reason string) {
+	if before == after {
+		return
+	}
+
+	r.appendStockMovement(ctx, models.StockMovement{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  after - before,
+		Balance:   after,
+		Reason:    reason,
+	})
+}
+
+// appendStockMovement must be called with the write lock held.
+func (r *MemoryProductRepository) appendStockMovement(ctx context.Context, movement models.StockMovement) models.StockMovement {
+	movement.ID = r.nextMovementID
+	movement.Actor = audit.ActorFromContext(ctx)
+	movement.RequestID = audit.RequestIDFromContext(ctx)
+	movement.CreatedAt = time.Now()
+
+	r.nextMovementID++
+	r.movements = append(r.movements, movement)
+	return movement
+}
+
+func (r *MemoryProductRepository) GetStockMovements(ctx context.Context, productID int, filter models.StockMovementFilter, nextToken models.NextTokenRequest) ([]models.StockMovement, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var movements []models.StockMovement
+	for _, movement := range r.movements {
+		if movement.ProductID != productID {
+			continue
+		}
+		if filter.Reason != "" && movement.Reason != filter.Reason {
+			continue
+		}
+		if filter.VariantID != nil && (movement.VariantID == nil || *movement.VariantID != *filter.VariantID) {
+			continue
+		}
+		if nextToken.Row > 0 {
+			if nextToken.Order == "asc" && movement.ID <= int64(nextToken.Row) {
+				continue
+			}
+			if nextToken.Order != "asc" && movement.ID >= int64(nextToken.Row) {
+				continue
+			}
+		}
+		movements = append(movements, movement)
+	}
+
+	sort.Slice(movements, func(i, j int) bool {
+		if nextToken.Order == "asc" {
+			return movements[i].ID < movements[j].ID
+		}
+		return movements[i].ID > movements[j].ID
+	})
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	if len(movements) > limit+1 {
+		movements = movements[:limit+1]
+	}
+
+	return movements, nil
+}
+
+func (r *MemoryProductRepository) ReconcileStock(ctx context.Context, filter models.ReconciliationFilter) ([]models.StockReconciliation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := r.products[filter.ProductID]; filter.ProductID != 0 && !ok {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", filter.ProductID)
+	}
+
+	productLedger := make(map[int]int)
+	variantLedger := make(map[int]int)
+	for _, movement := range r.movements {
+		if movement.VariantID != nil {
+			variantLedger[*movement.VariantID] += movement.Quantity
+		} else {
+			productLedger[movement.ProductID] += movement.Quantity
+		}
+	}
+
+	var results []models.StockReconciliation
+	add := func(result models.StockReconciliation) {
+		result.Difference = result.StockQuantity - result.LedgerQuantity
+		if !filter.DiscrepanciesOnly || result.Difference != 0 {
+			results = append(results, result)
+		}
+	}
+	for id, product := range r.products {
+		if filter.ProductID != 0 && id != filter.ProductID {
+			continue
+		}
+		add(models.StockReconciliation{ProductID: id, StockQuantity: product.StockQuantity, LedgerQuantity: productLedger[id]})
+		for _, variant := range product.Variants {
+			variantID := variant.ID
+			add(models.StockReconciliation{ProductID: id, VariantID: &variantID, StockQuantity: variant.StockQuantity, LedgerQuantity: variantLedger[variantID]})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].ProductID != results[j].ProductID {
+			return results[i].ProductID < results[j].ProductID
+		}
+		return results[i].VariantID == nil && results[j].VariantID != nil
+	})
+
+	return results, nil
+}
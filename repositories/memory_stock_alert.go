@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *MemoryProductRepository) GetLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	products := []models.Product{}
+	for _, product := range r.products {
+		if product.DeletedAt != nil || product.StockStatus == models.StockStatusInStock {
+			continue
+		}
+		if filter.Status != "" && product.StockStatus != filter.Status {
+			continue
+		}
+		products = append(products, product)
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if (a.TotalAvailable > 0) != (b.TotalAvailable > 0) {
+			return a.TotalAvailable <= 0
+		}
+		if a.TotalAvailable-a.ReorderPoint != b.TotalAvailable-b.ReorderPoint {
+			return a.TotalAvailable-a.ReorderPoint < b.TotalAvailable-b.ReorderPoint
+		}
+		return a.ID < b.ID
+	})
+
+	return products, nil
+}
+
+func (r *MemoryProductRepository) StockStatusChanges(ctx context.Context) ([]models.StockStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var changes []models.StockStatusChange
+	for id, product := range r.products {
+		if product.DeletedAt != nil {
+			continue
+		}
+		previous := r.alertStatus(id)
+		if product.StockStatus != previous {
+			changes = append(changes, models.StockStatusChange{Product: product, Previous: previous})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Product.ID < changes[j].Product.ID
+	})
+
+	return changes, nil
+}
+
+func (r *MemoryProductRepository) AcknowledgeStockStatus(ctx context.Context, productID int, status string) error {
+	if err := ctx.Err(); err != nil {
+		return translateError(err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.products[productID]; ok {
+		r.alertStatuses[productID] = status
+	}
+	return nil
+}
+
+// alertStatus mirrors the stock_alert_status column, which defaults to in
+// stock. It must be called with the lock held.
+func (r *MemoryProductRepository) alertStatus(productID int) string {
+	if status, ok := r.alertStatuses[productID]; ok {
+		return status
+	}
+	return models.StockStatusInStock
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"sync"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// memoryStore holds the data shared by the in-memory repositories behind a
+// single lock, so operations touching products and categories stay atomic.
+type memoryStore struct {
+	mu             sync.RWMutex
+	products       map[int]models.Product
+	nextID         int
+	history        []models.ProductHistory
+	nextHistoryID  int64
+	categories     map[int]models.Category
+	nextCategoryID int
+	nextVariantID  int
+	nextImageID    int
+	movements      []models.StockMovement
+	nextMovementID int64
+	reservations   map[int64]models.StockReservation
+	nextReserveID  int64
+	alertStatuses  map[int]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		products:       make(map[int]models.Product),
+		nextID:         1,
+		nextHistoryID:  1,
+		categories:     make(map[int]models.Category),
+		nextCategoryID: 1,
+		nextVariantID:  1,
+		nextImageID:    1,
+		nextMovementID: 1,
+		reservations:   make(map[int64]models.StockReservation),
+		nextReserveID:  1,
+		alertStatuses:  make(map[int]string),
+	}
+}
+
+// categorySummary must be called with the lock held.
+func (s *memoryStore) categorySummary(id *int) *models.CategorySummary {
+	if id == nil {
+		return nil
+	}
+	category, ok := s.categories[*id]
+	if !ok {
+		return nil
+	}
+	summary := category.Summary()
+	return &summary
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// trigrams splits text into the trigrams pg_trgm extracts: each word is
+// padded with two spaces before and one after.
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range searchWord.FindAllString(foldText(text), -1) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// wordSimilarity approximates pg_trgm word_similarity: the share of the
+// trigrams of term found in text.
+func wordSimilarity(term map[string]bool, text string) float32 {
+	if len(term) == 0 {
+		return 0
+	}
+	found := trigrams(text)
+	shared := 0
+	for trigram := range term {
+		if found[trigram] {
+			shared++
+		}
+	}
+	return float32(shared) / float32(len(term))
+}
+
+func (r *MemoryProductRepository) Suggest(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	term := trigrams(prefix)
+
+	var suggestions []models.Suggestion
+	for _, product := range r.products {
+		if product.DeletedAt != nil {
+			continue
+		}
+		if score := wordSimilarity(term, product.Name); score >= suggestThreshold {
+			suggestions = append(suggestions, models.Suggestion{Type: "product", ID: product.ID, Text: product.Name, Score: score})
+		}
+	}
+	for _, category := range r.categories {
+		if score := wordSimilarity(term, category.Name); score >= suggestThreshold {
+			suggestions = append(suggestions, models.Suggestion{Type: "category", ID: category.ID, Text: category.Name, Slug: category.Slug, Score: score})
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		switch {
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case len(a.Text) != len(b.Text):
+			return len(a.Text) < len(b.Text)
+		case a.Text != b.Text:
+			return a.Text < b.Text
+		}
+		return a.ID < b.ID
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// Facets counts the products matching filter per category, price range and
+// stock status. Each facet ignores the filter of its own dimension.
+func (r *ProductRepository) Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.ProductFacets, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	facets := &models.ProductFacets{}
+	if request.Category {
+		categories, err := r.categoryFacet(ctx, filter.WithoutCategory())
+		if err != nil {
+			return nil, err
+		}
+		facets.Categories = categories
+	}
+	if request.Price {
+		ranges, err := r.priceFacet(ctx, filter.WithoutPrice(), request.PriceBuckets)
+		if err != nil {
+			return nil, err
+		}
+		facets.PriceRanges = ranges
+	}
+	if request.Stock {
+		statuses, err := r.stockFacet(ctx, filter.WithoutStock())
+		if err != nil {
+			return nil, err
+		}
+		facets.StockStatus = statuses
+	}
+
+	return facets, nil
+}
+
+func (r *ProductRepository) categoryFacet(ctx context.Context, filter models.ProductFilter) ([]models.CategoryFacet, error) {
+	conditions, args, err := filterConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.slug, c.name, f.count
+		FROM (
+			SELECT category_id, COUNT(*) AS count
+			FROM products
+			WHERE deleted_at IS NULL`+conditions+`
+			GROUP BY category_id
+		) f
+		JOIN categories c ON c.id = f.category_id
+		ORDER BY f.count DESC, c.name, c.id
+	`, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	categories := []models.CategoryFacet{}
+	for rows.Next() {
+		var facet models.CategoryFacet
+		if err := rows.Scan(&facet.ID, &facet.Slug, &facet.Name, &facet.Count); err != nil {
+			return nil, translateError(err)
+		}
+		categories = append(categories, facet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return categories, nil
+}
+
+// priceFacet relies on width_bucket, which returns 0 below the first boundary
+// and i from the i-th boundary on, matching the order of models.PriceRanges.
+func (r *ProductRepository) priceFacet(ctx context.Context, filter models.ProductFilter, boundaries []money.Money) ([]models.PriceRangeFacet, error) {
+	ranges := models.PriceRanges(boundaries)
+
+	conditions, args, err := filterConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make([]string, len(boundaries))
+	for i, boundary := range boundaries {
+		bounds[i] = boundary.String()
+	}
+	args = append(args, pq.Array(bounds))
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT width_bucket(price, $%d::numeric[]) AS bucket, COUNT(*)
+		FROM (
+			SELECT `+sortExpr(models.SortPrice)+` AS price
+			FROM products
+			WHERE deleted_at IS NULL`+conditions+`
+		) p
+		GROUP BY bucket
+	`, len(args)), args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, translateError(err)
+		}
+		if bucket >= 0 && bucket < len(ranges) {
+			ranges[bucket].Count = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return ranges, nil
+}
+
+func (r *ProductRepository) stockFacet(ctx context.Context, filter models.ProductFilter) ([]models.StockStatusFacet, error) {
+	conditions, args, err := filterConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	inStock := models.StockStatusFacet{Status: models.StockStatusInStock}
+	low := models.StockStatusFacet{Status: models.StockStatusLow}
+	outOfStock := models.StockStatusFacet{Status: models.StockStatusOutOfStock}
+	err = r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'in_stock'),
+			COUNT(*) FILTER (WHERE status = 'low'),
+			COUNT(*) FILTER (WHERE status = 'out_of_stock')
+		FROM (
+			SELECT `+stockStatusExpr+` AS status
+			FROM products
+			WHERE deleted_at IS NULL`+conditions+`
+		) s
+	`, args...).Scan(&inStock.Count, &low.Count, &outOfStock.Count)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return []models.StockStatusFacet{inStock, low, outOfStock}, nil
+}
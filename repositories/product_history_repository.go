@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func (r *ProductRepository) insertHistory(ctx context.Context, tx *sql.Tx, productID int, action string, before, after *models.Product) error {
+	entry, err := newHistoryEntry(ctx, productID, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	beforeJSON, err := nullableJSON(entry.Before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := nullableJSON(entry.After)
+	if err != nil {
+		return err
+	}
+	changesJSON, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO product_history (product_id, action, actor, request_id, before, after, changes, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+	`, entry.ProductID, entry.Action, entry.Actor, entry.RequestID, beforeJSON, afterJSON, string(changesJSON), entry.CreatedAt)
+	return translateError(err)
+}
+
+func (r *ProductRepository) GetHistory(ctx context.Context, productID int, nextToken models.NextTokenRequest) ([]models.ProductHistory, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	query := `
+		SELECT id, product_id, action, actor, COALESCE(request_id, ''), before, after, changes, created_at
+		FROM product_history
+		WHERE product_id = $1
+	`
+	args := []interface{}{productID}
+
+	if nextToken.Row > 0 {
+		if nextToken.Order == "asc" {
+			query += " AND id > $2"
+		} else {
+			query += " AND id < $2"
+		}
+		args = append(args, nextToken.Row)
+	}
+
+	if nextToken.Order == "asc" {
+		query += " ORDER BY id ASC"
+	} else {
+		query += " ORDER BY id DESC"
+	}
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var history []models.ProductHistory
+	for rows.Next() {
+		var entry models.ProductHistory
+		var before, after, changes []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ProductID,
+			&entry.Action,
+			&entry.Actor,
+			&entry.RequestID,
+			&before,
+			&after,
+			&changes,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err)
+		}
+
+		if before != nil {
+			if err := json.Unmarshal(before, &entry.Before); err != nil {
+				return nil, err
+			}
+		}
+		if after != nil {
+			if err := json.Unmarshal(after, &entry.After); err != nil {
+				return nil, err
+			}
+		}
+		var stored map[string]models.FieldChange
+		if err := decodeJSON(changes, &stored); err != nil {
+			return nil, err
+		}
+		if entry.Changes, err = presentChanges(stored); err != nil {
+			return nil, err
+		}
+
+		history = append(history, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	// An empty page is only an error when the product never existed: the
+	// history of a purged product is kept after its row is gone.
+	if len(history) == 0 {
+		var known bool
+		err := r.db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)
+				OR EXISTS (SELECT 1 FROM product_history WHERE product_id = $1)
+		`, productID).Scan(&known)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		if !known {
+			return nil, apperrors.NotFound("produto com ID %d não encontrado", productID)
+		}
+	}
+
+	return history, nil
+}
+
+func nullableJSON(product *models.Product) (interface{}, error) {
+	if product == nil {
+		return nil, nil
+	}
+
+	fields, err := productFields(product)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
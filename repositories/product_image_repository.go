@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// imagesColumn aggregates the product images in display order.
+const imagesColumn = `(
+	SELECT COALESCE(json_agg(json_build_object(
+		'id', i.id,
+		'key', i.storage_key,
+		'content_type', i.content_type,
+		'size', i.size_bytes,
+		'position', i.position,
+		'primary', i.is_primary
+	) ORDER BY i.position, i.id), '[]')
+	FROM product_images i
+	WHERE i.product_id = products.id
+) AS images`
+
+// storedImage is an element of imagesColumn, which carries the storage key
+// that ProductImage hides from clients.
+type storedImage struct {
+	models.ProductImage
+	Key string `json:"key"`
+}
+
+// AddImage records an image already written to the image storage. It is
+// placed after the existing images and becomes the primary one when requested
+// or when it is the first image of the product.
+func (r *ProductRepository) AddImage(ctx context.Context, productID int, image models.ProductImage, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		var position int
+		var first bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COALESCE(MAX(position), 0) + 1, COUNT(*) = 0
+			FROM product_images
+			WHERE product_id = $1
+		`, productID).Scan(&position, &first); err != nil {
+			return models.Product{}, err
+		}
+
+		primary := image.Primary || first
+		if primary {
+			if _, err := tx.ExecContext(ctx, `UPDATE product_images SET is_primary = FALSE WHERE product_id = $1 AND is_primary`, productID); err != nil {
+				return models.Product{}, err
+			}
+		}
+
+		now := time.Now()
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO product_images (product_id, storage_key, content_type, size_bytes, position, is_primary, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, productID, image.Key, image.ContentType, image.Size, position, primary, now); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, now, productID))
+	})
+}
+
+func (r *ProductRepository) SetPrimaryImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		// The unique index on the primary flag is checked row by row, so the
+		// old primary image is cleared before the new one is set.
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE product_images SET is_primary = FALSE
+			WHERE product_id = $1 AND is_primary AND id <> $2
+		`, productID, imageID); err != nil {
+			return models.Product{}, err
+		}
+
+		result, err := tx.ExecContext(ctx, `UPDATE product_images SET is_primary = TRUE WHERE id = $1 AND product_id = $2`, imageID, productID)
+		if err != nil {
+			return models.Product{}, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return models.Product{}, apperrors.NotFound("imagem com ID %d não encontrada no produto %d", imageID, productID)
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), productID))
+	})
+}
+
+// ReorderImages expects every image ID of the product exactly once, in the new order.
+func (r *ProductRepository) ReorderImages(ctx context.Context, productID int, imageIDs []int, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		var current []int64
+		if err := tx.QueryRowContext(ctx, `
+			SELECT COALESCE(array_agg(id), '{}') FROM product_images WHERE product_id = $1
+		`, productID).Scan(pq.Array(&current)); err != nil {
+			return models.Product{}, err
+		}
+
+		ids := make([]int, len(current))
+		for i, id := range current {
+			ids[i] = int(id)
+		}
+		if err := checkImageOrder(ids, imageIDs); err != nil {
+			return models.Product{}, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE product_images
+			SET position = array_position($2::int[], id)
+			WHERE product_id = $1
+		`, productID, pq.Array(imageIDs)); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), productID))
+	})
+}
+
+// DeleteImage removes the image record and returns it, so the caller can
+// remove the file from the image storage. When the primary image is removed,
+// the next one in order takes its place.
+func (r *ProductRepository) DeleteImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, *models.ProductImage, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var removed models.ProductImage
+	product, err := r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		err := tx.QueryRowContext(ctx, `
+			DELETE FROM product_images
+			WHERE id = $1 AND product_id = $2
+			RETURNING id, storage_key, content_type, size_bytes, position, is_primary
+		`, imageID, productID).Scan(&removed.ID, &removed.Key, &removed.ContentType, &removed.Size, &removed.Position, &removed.Primary)
+		if err == sql.ErrNoRows {
+			return models.Product{}, apperrors.NotFound("imagem com ID %d não encontrada no produto %d", imageID, productID)
+		}
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE product_images i
+			SET position = o.position
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) AS position
+				FROM product_images
+				WHERE product_id = $1
+			) o
+			WHERE i.id = o.id
+		`, productID); err != nil {
+			return models.Product{}, err
+		}
+
+		if removed.Primary {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE product_images SET is_primary = TRUE
+				WHERE product_id = $1 AND position = 1
+			`, productID); err != nil {
+				return models.Product{}, err
+			}
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), productID))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return product, &removed, nil
+}
+
+// checkImageOrder verifies that ids is a permutation of current.
+func checkImageOrder(current, ids []int) error {
+	remaining := make(map[int]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+
+	for _, id := range ids {
+		if !remaining[id] {
+			return apperrors.Validation("a imagem %d não pertence ao produto ou aparece mais de uma vez", id)
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return apperrors.Validation("a nova ordem precisa incluir todas as %d imagens do produto", len(current))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// pricesColumn aggregates the product prices into a JSON object keyed by
+// currency; amounts are sent as text so no precision is lost.
+const pricesColumn = `(
+	SELECT COALESCE(json_object_agg(pp.currency, pp.amount::text), '{}')
+	FROM product_prices pp
+	WHERE pp.product_id = products.id
+) AS prices`
+
+// replacePrices swaps every price of the product for the given ones.
+func (r *ProductRepository) replacePrices(ctx context.Context, tx *sql.Tx, productID int, prices map[string]money.Money) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_prices WHERE product_id = $1`, productID); err != nil {
+		return translateError(err)
+	}
+
+	for currency, amount := range prices {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO product_prices (product_id, currency, amount)
+			VALUES ($1, $2, $3)
+		`, productID, currency, amount); err != nil {
+			return translateError(err)
+		}
+	}
+
+	return nil
+}
@@ -1,344 +1,642 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/gtin"
 	"github.com/seuusuario/api-rest-go/models"
 )
 
+const productColumns = `id, name, description, COALESCE(sku, ''), COALESCE(gtin, ''), category_id, stock_quantity, reserved_quantity, COALESCE(reorder_point, 0), COALESCE(reorder_quantity, 0), version, created_at, updated_at, deleted_at, ` +
+	pricesColumn + `, ` + categoryColumn + `, ` + tagsColumn + `, ` + variantsColumn + `, ` + imagesColumn
+
+// categoryColumn embeds the current slug and name of the product category.
+const categoryColumn = `(
+	SELECT json_build_object('id', c.id, 'slug', c.slug, 'name', c.name)
+	FROM categories c
+	WHERE c.id = products.category_id
+) AS category`
+
 type ProductRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	timeouts QueryTimeouts
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+func NewProductRepository(db *sql.DB, timeouts QueryTimeouts) *ProductRepository {
+	return &ProductRepository{db: db, timeouts: timeouts}
 }
 
-func (r *ProductRepository) GetAll() ([]models.Product, error) {
-	query := `
-		SELECT id, name, description, price, category, stock_quantity, created_at, updated_at
-		FROM products
-		ORDER BY created_at DESC
-	`
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	rows, err := r.db.Query(query)
+func scanProduct(row rowScanner) (models.Product, error) {
+	var product models.Product
+	var prices, category, tags, variants, images []byte
+	err := row.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Description,
+		&product.SKU,
+		&product.GTIN,
+		&product.CategoryID,
+		&product.StockQuantity,
+		&product.ReservedQuantity,
+		&product.ReorderPoint,
+		&product.ReorderQuantity,
+		&product.Version,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.DeletedAt,
+		&prices,
+		&category,
+		&tags,
+		&variants,
+		&images,
+	)
 	if err != nil {
-		return nil, err
+		return product, err
+	}
+
+	if category != nil {
+		if err := json.Unmarshal(category, &product.Category); err != nil {
+			return product, err
+		}
+	}
+
+	if err := json.Unmarshal(tags, &product.Tags); err != nil {
+		return product, err
+	}
+
+	if err := json.Unmarshal(variants, &product.Variants); err != nil {
+		return product, err
+	}
+	product.SummarizeVariants()
+
+	var stored []storedImage
+	if err := json.Unmarshal(images, &stored); err != nil {
+		return product, err
+	}
+	product.Images = make([]models.ProductImage, len(stored))
+	for i, image := range stored {
+		product.Images[i] = image.ProductImage
+		product.Images[i].Key = image.Key
+	}
+
+	err = json.Unmarshal(prices, &product.Prices)
+	return product, err
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (r *ProductRepository) queryProducts(ctx context.Context, query string, args ...interface{}) ([]models.Product, error) {
+	return r.queryProductsTx(ctx, r.db, query, args...)
+}
+
+func (r *ProductRepository) queryProductsTx(ctx context.Context, q queryer, query string, args ...interface{}) ([]models.Product, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
 	var products []models.Product
 	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Category,
-			&product.StockQuantity,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
+		product, err := scanProduct(rows)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err)
 		}
 		products = append(products, product)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
 
 	return products, nil
 }
 
-func (r *ProductRepository) GetByID(id int) (*models.Product, error) {
+func (r *ProductRepository) GetByID(ctx context.Context, id int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
 	query := `
-		SELECT id, name, description, price, category, stock_quantity, created_at, updated_at
+		SELECT ` + productColumns + `
 		FROM products
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
-	row := r.db.QueryRow(query, id)
-
-	var product models.Product
-	err := row.Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.Category,
-		&product.StockQuantity,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
-
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("produto com ID %d não encontrado", id)
+			return nil, apperrors.NotFound("produto com ID %d não encontrado", id)
 		}
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	return &product, nil
 }
 
-func (r *ProductRepository) Create(req models.CreateProductRequest) (*models.Product, error) {
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
 	query := `
-		INSERT INTO products (name, description, price, category, stock_quantity, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, name, description, price, category, stock_quantity, created_at, updated_at
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE sku = $1 AND deleted_at IS NULL
 	`
 
-	now := time.Now()
-	row := r.db.QueryRow(query, req.Name, req.Description, req.Price, req.Category, req.StockQuantity, now, now)
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, sku))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("produto com SKU %s não encontrado", sku)
+		}
+		return nil, translateError(err)
+	}
 
-	var product models.Product
-	err := row.Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.Category,
-		&product.StockQuantity,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	return &product, nil
+}
+
+// GetByGTIN compares codes padded to 14 digits, matching the expression
+// behind idx_products_gtin.
+func (r *ProductRepository) GetByGTIN(ctx context.Context, code string) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE LPAD(gtin, 14, '0') = $1 AND deleted_at IS NULL
+	`
 
+	product, err := scanProduct(r.db.QueryRowContext(ctx, query, gtin.Normalize(code)))
 	if err != nil {
-		return nil, err
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("produto com código de barras %s não encontrado", code)
+		}
+		return nil, translateError(err)
 	}
 
 	return &product, nil
 }
 
-func (r *ProductRepository) Update(id int, req models.UpdateProductRequest) (*models.Product, error) {
-	existing, err := r.GetByID(id)
+func (r *ProductRepository) Create(ctx context.Context, req models.CreateProductRequest) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO products (name, description, sku, gtin, category_id, stock_quantity, reorder_point, reorder_quantity, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5::int, 0), $6, NULLIF($7, 0), NULLIF($8, 0), $9, $10)
+		RETURNING ` + productColumns
 
-	if req.Name != "" {
-		existing.Name = req.Name
+	now := time.Now()
+	product, err := scanProduct(tx.QueryRowContext(ctx, query, req.Name, req.Description, req.SKU, req.GTIN, req.CategoryID, req.StockQuantity,
+		req.ReorderPoint, req.ReorderQuantity, now, now))
+	if err != nil {
+		return nil, translateError(err)
 	}
-	if req.Description != "" {
-		existing.Description = req.Description
+
+	if err := r.replacePrices(ctx, tx, product.ID, req.Prices); err != nil {
+		return nil, err
 	}
-	if req.Price > 0 {
-		existing.Price = req.Price
+	product.Prices = req.Prices
+
+	if err := r.recordStockChange(ctx, tx, product.ID, nil, 0, product.StockQuantity, models.StockReasonInitial); err != nil {
+		return nil, err
 	}
-	if req.Category != "" {
-		existing.Category = req.Category
+
+	if err := r.insertHistory(ctx, tx, product.ID, models.HistoryActionCreate, nil, &product); err != nil {
+		return nil, err
 	}
-	if req.StockQuantity >= 0 {
-		existing.StockQuantity = req.StockQuantity
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err)
 	}
-	existing.UpdatedAt = time.Now()
+
+	return &product, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int, req models.UpdateProductRequest, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
 	query := `
 		UPDATE products
-		SET name = $1, description = $2, price = $3, category = $4, stock_quantity = $5, updated_at = $6
-		WHERE id = $7
-		RETURNING id, name, description, price, category, stock_quantity, created_at, updated_at
+		SET name = COALESCE($1, name),
+			description = COALESCE($2, description),
+			sku = CASE WHEN $3::text IS NULL THEN sku ELSE NULLIF($3, '') END,
+			gtin = CASE WHEN $4::text IS NULL THEN gtin ELSE NULLIF($4, '') END,
+			category_id = CASE WHEN $5::int IS NULL THEN category_id ELSE NULLIF($5::int, 0) END,
+			stock_quantity = COALESCE($6, stock_quantity),
+			reorder_point = CASE WHEN $9::int IS NULL THEN reorder_point ELSE NULLIF($9::int, 0) END,
+			reorder_quantity = CASE WHEN $10::int IS NULL THEN reorder_quantity ELSE NULLIF($10::int, 0) END,
+			version = version + 1,
+			updated_at = $7
+		WHERE id = $8
+		RETURNING ` + productColumns
+
+	return r.mutate(ctx, id, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		// Prices are written first so the RETURNING clause already sees them.
+		if req.Prices != nil {
+			if err := r.replacePrices(ctx, tx, id, req.Prices); err != nil {
+				return models.Product{}, err
+			}
+		}
+
+		stock, _, err := r.currentStock(ctx, tx, id, nil)
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		product, err := scanProduct(tx.QueryRowContext(ctx, query, req.Name, req.Description, req.SKU, req.GTIN,
+			req.CategoryID, req.StockQuantity, time.Now(), id, req.ReorderPoint, req.ReorderQuantity))
+		if err != nil {
+			return models.Product{}, err
+		}
+		if err := belowReserved(product.StockQuantity, product.ReservedQuantity); err != nil {
+			return models.Product{}, err
+		}
+
+		// Setting the stock directly is recorded as a correction in the ledger.
+		return product, r.recordStockChange(ctx, tx, id, nil, stock, product.StockQuantity, models.StockReasonCorrection)
+	})
+}
+
+// Delete moves the product to the trash; it is only removed for good by PurgeDeleted.
+func (r *ProductRepository) Delete(ctx context.Context, id int, expectedVersion *int) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	query := `
+		UPDATE products
+		SET deleted_at = $1, version = version + 1, updated_at = $1
+		WHERE id = $2
+		RETURNING ` + productColumns
+
+	_, err := r.mutate(ctx, id, expectedVersion, false, models.HistoryActionDelete, func(tx *sql.Tx) (models.Product, error) {
+		return scanProduct(tx.QueryRowContext(ctx, query, time.Now(), id))
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Produto com ID %d movido para a lixeira", id)
+	return nil
+}
+
+func (r *ProductRepository) GetDeleted(ctx context.Context) ([]models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
 	`
 
-	row := r.db.QueryRow(query, existing.Name, existing.Description, existing.Price,
-		existing.Category, existing.StockQuantity, existing.UpdatedAt, id)
+	return r.queryProducts(ctx, query)
+}
 
-	var product models.Product
-	err = row.Scan(
-		&product.ID,
-		&product.Name,
-		&product.Description,
-		&product.Price,
-		&product.Category,
-		&product.StockQuantity,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+func (r *ProductRepository) Restore(ctx context.Context, id int, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
 
+	query := `
+		UPDATE products
+		SET deleted_at = NULL, version = version + 1, updated_at = $1
+		WHERE id = $2
+		RETURNING ` + productColumns
+
+	product, err := r.mutate(ctx, id, expectedVersion, true, models.HistoryActionRestore, func(tx *sql.Tx) (models.Product, error) {
+		return scanProduct(tx.QueryRowContext(ctx, query, time.Now(), id))
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &product, nil
+	log.Printf("Produto com ID %d restaurado da lixeira", id)
+	return product, nil
 }
 
-func (r *ProductRepository) Delete(id int) error {
-	_, err := r.GetByID(id)
+func (r *ProductRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return nil, translateError(err)
 	}
+	defer tx.Rollback()
 
-	query := `DELETE FROM products WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+	purged, err := r.queryProductsTx(ctx, tx, `
+		DELETE FROM products
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+		RETURNING `+productColumns, deletedBefore)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	for i := range purged {
+		if err := r.insertHistory(ctx, tx, purged[i].ID, models.HistoryActionPurge, &purged[i], nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return purged, nil
+}
+
+// mutate runs a guarded write on a single product inside a transaction: the row
+// is locked, checked against the expected trash state and version, changed by
+// write and the change is recorded in the product history.
+func (r *ProductRepository) mutate(ctx context.Context, id int, expectedVersion *int, expectDeleted bool, action string, write func(tx *sql.Tx) (models.Product, error)) (*models.Product, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	before, err := scanProduct(tx.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products WHERE id = $1 FOR UPDATE`, id))
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("produto com ID %d não encontrado", id)
+		}
+		return nil, translateError(err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("produto com ID %d não encontrado", id)
+	deleted := before.DeletedAt != nil
+	if deleted != expectDeleted || (expectedVersion != nil && *expectedVersion != before.Version) {
+		return nil, stateConflict(id, before.Version, deleted, expectDeleted)
 	}
 
-	log.Printf("Produto com ID %d removido com sucesso", id)
-	return nil
+	after, err := write(tx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := r.insertHistory(ctx, tx, id, action, &before, &after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &after, nil
 }
 
-func (r *ProductRepository) GetByCategory(category string) ([]models.Product, error) {
-	query := `
-		SELECT id, name, description, price, category, stock_quantity, created_at, updated_at
+// estimateThreshold is the planner estimate below which the estimated count
+// mode runs an exact COUNT(*) anyway, since small sets are cheap to count.
+const estimateThreshold = 10000
+
+func (r *ProductRepository) FindByFilter(ctx context.Context, filter models.ProductFilter, nextToken models.NextTokenRequest, count models.CountMode) ([]models.Product, *models.Total, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	baseQuery := `
+		SELECT ` + productColumns + `
 		FROM products
-		WHERE category = $1
-		ORDER BY created_at DESC
+		WHERE deleted_at IS NULL
 	`
 
-	rows, err := r.db.Query(query, category)
+	conditions, args, err := filterConditions(filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer rows.Close()
 
-	var products []models.Product
-	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Category,
-			&product.StockQuantity,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
+	// The total covers the whole filtered set, so it is computed before the
+	// cursor condition is added.
+	total, err := r.countFiltered(ctx, conditions, args, count)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	argIndex := len(args) + 1
+
+	keys := nextToken.Keys()
+	expressions := make([]string, len(keys))
+	for i, key := range keys {
+		expressions[i] = sortExpr(key.Field)
+	}
+
+	// Keyset pagination over any number of keys and directions: a row comes
+	// after the cursor when it is past it on the first key, or ties on the
+	// first keys and is past it on the next one.
+	if keys[0].After != nil {
+		var alternatives []string
+		for i, key := range keys {
+			var parts []string
+			for j := 0; j < i; j++ {
+				parts = append(parts, fmt.Sprintf("%s = $%d", expressions[j], argIndex+j))
+			}
+			operator := ">"
+			if key.Desc {
+				operator = "<"
+			}
+			parts = append(parts, fmt.Sprintf("%s %s $%d", expressions[i], operator, argIndex+i))
+			alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+		}
+		conditions += " AND (" + strings.Join(alternatives, " OR ") + ")"
+		for _, key := range keys {
+			args = append(args, key.After)
+		}
+		argIndex += len(keys)
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = expressions[i] + " ASC"
+		if key.Desc {
+			order[i] = expressions[i] + " DESC"
+		}
+	}
+	orderClause := " ORDER BY " + strings.Join(order, ", ")
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+
+	limitClause := fmt.Sprintf(" LIMIT $%d", argIndex)
+	args = append(args, limit+1)
+	if nextToken.Offset > 0 {
+		limitClause += fmt.Sprintf(" OFFSET $%d", argIndex+1)
+		args = append(args, nextToken.Offset)
+	}
+
+	finalQuery := baseQuery + conditions + orderClause + limitClause
+	products, err := r.queryProducts(ctx, finalQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return products, total, nil
+}
+
+// countFiltered returns the number of products matching conditions, or nil
+// when count is CountNone. The estimated mode reads the row estimate of the
+// query plan, which relies on the table statistics kept by ANALYZE.
+func (r *ProductRepository) countFiltered(ctx context.Context, conditions string, args []interface{}, count models.CountMode) (*models.Total, error) {
+	if count == models.CountNone {
+		return nil, nil
+	}
+
+	if count == models.CountEstimated {
+		var plan []byte
+		err := r.db.QueryRowContext(ctx, `EXPLAIN (FORMAT JSON) SELECT 1 FROM products WHERE deleted_at IS NULL`+conditions, args...).Scan(&plan)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err)
+		}
+		var explained []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal(plan, &explained); err != nil || len(explained) == 0 {
+			return nil, fmt.Errorf("plano de execução inesperado: %s", plan)
+		}
+		if estimate := int(explained[0].Plan.Rows); estimate >= estimateThreshold {
+			return &models.Total{Count: estimate, Estimated: true}, nil
 		}
-		products = append(products, product)
 	}
 
-	return products, nil
+	var total models.Total
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`+conditions, args...).Scan(&total.Count)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &total, nil
 }
 
-func (r *ProductRepository) FindByFilter(filter models.ProductFilter, nextToken models.NextTokenRequest) ([]models.Product, int, error) {
-	baseQuery := `
-		SELECT id, name, description, price, category, stock_quantity, created_at, updated_at
-		FROM products
-		WHERE 1=1
-	`
+// sortExpr returns the SQL expression of a sort field. The price is read in
+// the currency of the filter, which filterConditions binds to $1. Names are
+// compared by code point with the "C" collation, whatever the locale of the
+// database, so the order is the same as in the memory store.
+func sortExpr(field string) string {
+	switch field {
+	case models.SortName:
+		return `name COLLATE "C"`
+	case models.SortPrice:
+		return "(SELECT pp.amount FROM product_prices pp WHERE pp.product_id = products.id AND pp.currency = $1)"
+	case models.SortCreatedAt:
+		return "created_at"
+	case models.SortUpdatedAt:
+		return "updated_at"
+	case models.SortStock:
+		return totalStockExpr
+	}
+	return "id"
+}
 
-	countQuery := `
-		SELECT COUNT(*)
-		FROM products
-		WHERE 1=1
-	`
+// likeEscaper escapes the LIKE wildcards, so the name filter matches the text
+// as typed, like the in-memory strings.Contains.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
 
-	var args []interface{}
+// filterConditions builds the WHERE conditions of filter, to be appended after
+// "WHERE deleted_at IS NULL" in a query over products, and their arguments.
+// The first argument is always the currency, so $1 can be used by other
+// expressions of the query.
+func filterConditions(filter models.ProductFilter) (string, []interface{}, error) {
+	args := []interface{}{filter.Currency}
 	var conditions string
-	argIndex := 1
+	argIndex := 2
 
-	// Build filter conditions
 	if filter.Name != "" {
-		conditions += fmt.Sprintf(" AND name ILIKE $%d", argIndex)
-		args = append(args, "%"+filter.Name+"%")
+		conditions += fmt.Sprintf(` AND name ILIKE $%d ESCAPE '\'`, argIndex)
+		args = append(args, "%"+escapeLike(filter.Name)+"%")
+		argIndex++
+	}
+
+	if filter.CategoryIDs != nil {
+		conditions += fmt.Sprintf(" AND category_id = ANY($%d)", argIndex)
+		args = append(args, pq.Array(filter.CategoryIDs))
 		argIndex++
 	}
 
-	if filter.Category != "" {
-		conditions += fmt.Sprintf(" AND category = $%d", argIndex)
-		args = append(args, filter.Category)
+	if len(filter.TagsAny) > 0 {
+		conditions += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM product_tags pt JOIN tags t ON t.id = pt.tag_id"+
+			" WHERE pt.product_id = products.id AND t.name = ANY($%d))", argIndex)
+		args = append(args, pq.Array(filter.TagsAny))
 		argIndex++
 	}
 
+	if len(filter.TagsAll) > 0 {
+		conditions += fmt.Sprintf(" AND (SELECT COUNT(*) FROM product_tags pt JOIN tags t ON t.id = pt.tag_id"+
+			" WHERE pt.product_id = products.id AND t.name = ANY($%d)) = $%d", argIndex, argIndex+1)
+		args = append(args, pq.Array(filter.TagsAll), len(filter.TagsAll))
+		argIndex += 2
+	}
+
+	// Only products priced in the requested currency are returned, and the
+	// price filters apply to that price.
+	priceConditions := "pp.currency = $1"
+
 	if filter.MinPrice != nil {
-		conditions += fmt.Sprintf(" AND price >= $%d", argIndex)
+		priceConditions += fmt.Sprintf(" AND pp.amount >= $%d", argIndex)
 		args = append(args, *filter.MinPrice)
 		argIndex++
 	}
 
 	if filter.MaxPrice != nil {
-		conditions += fmt.Sprintf(" AND price <= $%d", argIndex)
+		priceConditions += fmt.Sprintf(" AND pp.amount <= $%d", argIndex)
 		args = append(args, *filter.MaxPrice)
 		argIndex++
 	}
 
+	conditions += " AND EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id AND " + priceConditions + ")"
+
+	if len(filter.Options) > 0 {
+		options, err := json.Marshal(filter.Options)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM product_variants v"+
+			" WHERE v.product_id = products.id AND v.options @> $%d::jsonb)", argIndex)
+		args = append(args, string(options))
+		argIndex++
+	}
+
 	if filter.MinStock != nil {
-		conditions += fmt.Sprintf(" AND stock_quantity >= $%d", argIndex)
+		conditions += fmt.Sprintf(" AND "+totalStockExpr+" >= $%d", argIndex)
 		args = append(args, *filter.MinStock)
 		argIndex++
 	}
 
 	if filter.MaxStock != nil {
-		conditions += fmt.Sprintf(" AND stock_quantity <= $%d", argIndex)
+		conditions += fmt.Sprintf(" AND "+totalStockExpr+" <= $%d", argIndex)
 		args = append(args, *filter.MaxStock)
 		argIndex++
 	}
 
-	if nextToken.Row > 0 {
-		if nextToken.Order == "asc" {
-			conditions += fmt.Sprintf(" AND id > $%d", argIndex)
-		} else {
-			conditions += fmt.Sprintf(" AND id < $%d", argIndex)
-		}
-		args = append(args, nextToken.Row)
+	if filter.StockStatus != "" {
+		conditions += fmt.Sprintf(" AND "+stockStatusExpr+" = $%d", argIndex)
+		args = append(args, filter.StockStatus)
 		argIndex++
 	}
 
-	// Get total count (create a copy of args without the limit parameter)
-	var total int
-	var countArgs []interface{}
-	if len(args) > 0 {
-		countArgs = make([]interface{}, len(args))
-		copy(countArgs, args)
-	}
-	err := r.db.QueryRow(countQuery+conditions, countArgs...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	orderClause := " ORDER BY id DESC"
-	if nextToken.Order == "asc" {
-		orderClause = " ORDER BY id ASC"
-	}
-
-	limit := nextToken.Limit
-	if limit == 0 {
-		limit = 10
-	}
-
-	limitClause := fmt.Sprintf(" LIMIT $%d", argIndex)
-	args = append(args, limit+1)
-
-	finalQuery := baseQuery + conditions + orderClause + limitClause
-	rows, err := r.db.Query(finalQuery, args...)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
-	var products []models.Product
-	for rows.Next() {
-		var product models.Product
-		err := rows.Scan(
-			&product.ID,
-			&product.Name,
-			&product.Description,
-			&product.Price,
-			&product.Category,
-			&product.StockQuantity,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-		products = append(products, product)
-	}
-
-	return products, total, nil
+	return conditions, args, nil
 }
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// searchConfig is the text search configuration created by the migrations.
+const searchConfig = `'portuguese_unaccent'`
+
+const headlineOptions = `'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'`
+
+const descriptionHeadlineOptions = `'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "'`
+
+// extraColumns lets scanProduct read rows that carry more columns after productColumns.
+type extraColumns struct {
+	row  rowScanner
+	dest []interface{}
+}
+
+func (e extraColumns) Scan(dest ...interface{}) error {
+	return e.row.Scan(append(dest, e.dest...)...)
+}
+
+// Search returns the products matching the query ordered by relevance, one
+// more than the page size so the caller can tell whether there are more pages.
+// Results are only returned when the product has a price in search.Currency.
+func (r *ProductRepository) Search(ctx context.Context, search models.ProductSearch, nextToken models.SearchNextToken) ([]models.ProductSearchResult, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	args := []interface{}{search.Query, search.Currency}
+	conditions := ""
+	if nextToken.Row > 0 {
+		conditions = " AND (ts_rank(search_vector, q.query), id) < ($3::real, $4)"
+		args = append(args, nextToken.Rank, nextToken.Row)
+	}
+	args = append(args, nextToken.Limit+1)
+
+	query := `
+		SELECT ` + productColumns + `,
+			ts_rank(search_vector, q.query) AS rank,
+			ts_headline(` + searchConfig + `, name, q.query, ` + headlineOptions + `),
+			ts_headline(` + searchConfig + `, COALESCE(description, ''), q.query, ` + descriptionHeadlineOptions + `)
+		FROM products, websearch_to_tsquery(` + searchConfig + `, $1) AS q(query)
+		WHERE deleted_at IS NULL
+			AND search_vector @@ q.query
+			AND EXISTS (SELECT 1 FROM product_prices pp WHERE pp.product_id = products.id AND pp.currency = $2)` +
+		conditions + `
+		ORDER BY rank DESC, id DESC
+		LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var results []models.ProductSearchResult
+	for rows.Next() {
+		var result models.ProductSearchResult
+		product, err := scanProduct(extraColumns{row: rows, dest: []interface{}{
+			&result.Rank,
+			&result.Highlights.Name,
+			&result.Highlights.Description,
+		}})
+		if err != nil {
+			return nil, translateError(err)
+		}
+		result.Product = product
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+type ProductStore interface {
+	GetByID(ctx context.Context, id int) (*models.Product, error)
+	GetBySKU(ctx context.Context, sku string) (*models.Product, error)
+	GetByGTIN(ctx context.Context, code string) (*models.Product, error)
+	Create(ctx context.Context, req models.CreateProductRequest) (*models.Product, error)
+	Update(ctx context.Context, id int, req models.UpdateProductRequest, expectedVersion *int) (*models.Product, error)
+	Delete(ctx context.Context, id int, expectedVersion *int) error
+	FindByFilter(ctx context.Context, filter models.ProductFilter, nextToken models.NextTokenRequest, count models.CountMode) ([]models.Product, *models.Total, error)
+	Search(ctx context.Context, search models.ProductSearch, nextToken models.SearchNextToken) ([]models.ProductSearchResult, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
+	Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.ProductFacets, error)
+	GetDeleted(ctx context.Context) ([]models.Product, error)
+	Restore(ctx context.Context, id int, expectedVersion *int) (*models.Product, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) ([]models.Product, error)
+	GetHistory(ctx context.Context, productID int, nextToken models.NextTokenRequest) ([]models.ProductHistory, error)
+	AddTags(ctx context.Context, id int, tags []string, expectedVersion *int) (*models.Product, error)
+	RemoveTag(ctx context.Context, id int, tag string, expectedVersion *int) (*models.Product, error)
+	CreateVariant(ctx context.Context, productID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error)
+	UpdateVariant(ctx context.Context, productID, variantID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error)
+	DeleteVariant(ctx context.Context, productID, variantID int, expectedVersion *int) (*models.Product, error)
+	AddImage(ctx context.Context, productID int, image models.ProductImage, expectedVersion *int) (*models.Product, error)
+	SetPrimaryImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, error)
+	ReorderImages(ctx context.Context, productID int, imageIDs []int, expectedVersion *int) (*models.Product, error)
+	DeleteImage(ctx context.Context, productID, imageID int, expectedVersion *int) (*models.Product, *models.ProductImage, error)
+	RecordStockMovement(ctx context.Context, productID int, req models.StockMovementRequest, expectedVersion *int) (*models.StockMovement, *models.Product, error)
+	GetStockMovements(ctx context.Context, productID int, filter models.StockMovementFilter, nextToken models.NextTokenRequest) ([]models.StockMovement, error)
+	ReconcileStock(ctx context.Context, filter models.ReconciliationFilter) ([]models.StockReconciliation, error)
+	ReserveStock(ctx context.Context, productID int, req models.ReservationRequest, expiresAt time.Time) (*models.StockReservation, error)
+	GetReservation(ctx context.Context, id int64) (*models.StockReservation, error)
+	ConfirmReservation(ctx context.Context, id int64) (*models.StockReservation, *models.Product, error)
+	CancelReservation(ctx context.Context, id int64) (*models.StockReservation, error)
+	ExpireReservations(ctx context.Context, now time.Time) (int, error)
+	GetLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.Product, error)
+	StockStatusChanges(ctx context.Context) ([]models.StockStatusChange, error)
+	AcknowledgeStockStatus(ctx context.Context, productID int, status string) error
+}
+
+type CategoryStore interface {
+	GetAll(ctx context.Context) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	Create(ctx context.Context, req models.CategoryRequest) (*models.Category, error)
+	Update(ctx context.Context, id int, req models.CategoryRequest) (*models.Category, error)
+	Delete(ctx context.Context, id int) error
+}
+
+var (
+	_ ProductStore  = (*ProductRepository)(nil)
+	_ ProductStore  = (*MemoryProductRepository)(nil)
+	_ CategoryStore = (*CategoryRepository)(nil)
+	_ CategoryStore = (*MemoryCategoryRepository)(nil)
+)
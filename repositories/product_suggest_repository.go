@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// suggestThreshold is the minimum word similarity of a suggestion. It is lower
+// than the pg_trgm default of 0.6 so that short prefixes with a typo still match.
+const suggestThreshold = 0.3
+
+// suggestTerm normalizes the typed text like the trigram indexes of the migrations.
+const suggestTerm = `immutable_unaccent(lower($1))`
+
+// Suggest returns the product and category names most similar to prefix,
+// best first. The <% operator compares the typed text with the closest part
+// of each name, so both incomplete words and typos match, and it is answered
+// by the trigram indexes.
+func (r *ProductRepository) Suggest(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Suggest)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer tx.Rollback()
+
+	// The operator threshold is a setting, limited here to this transaction.
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, fmt.Sprint(suggestThreshold)); err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT type, id, text, slug, score FROM (
+			SELECT 'product' AS type, id, name AS text, '' AS slug,
+				word_similarity(`+suggestTerm+`, immutable_unaccent(lower(name))) AS score
+			FROM products
+			WHERE deleted_at IS NULL AND `+suggestTerm+` <% immutable_unaccent(lower(name))
+			UNION ALL
+			SELECT 'category', id, name, slug,
+				word_similarity(`+suggestTerm+`, immutable_unaccent(lower(name)))
+			FROM categories
+			WHERE `+suggestTerm+` <% immutable_unaccent(lower(name))
+		) suggestions
+		ORDER BY score DESC, length(text), text, id
+		LIMIT $2
+	`, prefix, limit)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var suggestions []models.Suggestion
+	for rows.Next() {
+		var suggestion models.Suggestion
+		if err := rows.Scan(&suggestion.Type, &suggestion.ID, &suggestion.Text, &suggestion.Slug, &suggestion.Score); err != nil {
+			return nil, translateError(err)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return suggestions, nil
+}
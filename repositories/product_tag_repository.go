@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// tagsColumn aggregates the product tag names in alphabetical order.
+const tagsColumn = `(
+	SELECT COALESCE(json_agg(t.name ORDER BY t.name), '[]')
+	FROM product_tags pt
+	JOIN tags t ON t.id = pt.tag_id
+	WHERE pt.product_id = products.id
+) AS tags`
+
+// touchProductQuery bumps the version of a product whose tags or variants
+// changed, so its ETag reflects the new representation.
+const touchProductQuery = `
+	UPDATE products
+	SET version = version + 1, updated_at = $1
+	WHERE id = $2
+	RETURNING ` + productColumns
+
+// AddTags expects normalized tag names; tags the product already has are ignored.
+func (r *ProductRepository) AddTags(ctx context.Context, id int, tags []string, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, id, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO tags (name)
+			SELECT unnest($1::text[])
+			ON CONFLICT (name) DO NOTHING
+		`, pq.Array(tags)); err != nil {
+			return models.Product{}, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO product_tags (product_id, tag_id)
+			SELECT $1, id FROM tags WHERE name = ANY($2)
+			ON CONFLICT DO NOTHING
+		`, id, pq.Array(tags)); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), id))
+	})
+}
+
+func (r *ProductRepository) RemoveTag(ctx context.Context, id int, tag string, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, id, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		result, err := tx.ExecContext(ctx, `
+			DELETE FROM product_tags pt
+			USING tags t
+			WHERE t.id = pt.tag_id AND pt.product_id = $1 AND t.name = $2
+		`, id, tag)
+		if err != nil {
+			return models.Product{}, err
+		}
+		if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+			return models.Product{}, apperrors.NotFound("produto com ID %d não possui a tag %s", id, tag)
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), id))
+	})
+}
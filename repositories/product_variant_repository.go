@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// variantsColumn aggregates the product variants, with their own prices, in
+// creation order.
+const variantsColumn = `(
+	SELECT COALESCE(json_agg(json_build_object(
+		'id', v.id,
+		'sku', v.sku,
+		'options', v.options,
+		'prices', (
+			SELECT json_object_agg(vp.currency, vp.amount::text)
+			FROM product_variant_prices vp
+			WHERE vp.variant_id = v.id
+		),
+		'stock_quantity', v.stock_quantity,
+		'reserved_quantity', v.reserved_quantity
+	) ORDER BY v.id), '[]')
+	FROM product_variants v
+	WHERE v.product_id = products.id
+) AS variants`
+
+// totalStockExpr is the SQL counterpart of Product.TotalStock.
+const totalStockExpr = `COALESCE((SELECT SUM(v.stock_quantity) FROM product_variants v WHERE v.product_id = products.id), stock_quantity)`
+
+// availableStockExpr is the SQL counterpart of Product.TotalAvailable.
+const availableStockExpr = `COALESCE((SELECT SUM(v.stock_quantity - v.reserved_quantity) FROM product_variants v WHERE v.product_id = products.id), stock_quantity - reserved_quantity)`
+
+// stockStatusExpr is the SQL counterpart of models.StockStatusFor.
+const stockStatusExpr = `(CASE
+	WHEN ` + availableStockExpr + ` <= 0 THEN 'out_of_stock'
+	WHEN ` + availableStockExpr + ` <= COALESCE(reorder_point, 0) THEN 'low'
+	ELSE 'in_stock'
+END)`
+
+// CreateVariant adds a variant to the product and bumps the product version.
+func (r *ProductRepository) CreateVariant(ctx context.Context, productID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		if err := r.checkOptionNames(ctx, tx, productID, 0, req.Options); err != nil {
+			return models.Product{}, err
+		}
+
+		options, err := json.Marshal(req.Options)
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		now := time.Now()
+		var variantID int
+		if err := tx.QueryRowContext(ctx, `
+			INSERT INTO product_variants (product_id, sku, options, stock_quantity, created_at, updated_at)
+			VALUES ($1, NULLIF($2, ''), $3::jsonb, $4, $5, $6)
+			RETURNING id
+		`, productID, req.SKU, string(options), req.StockQuantity, now, now).Scan(&variantID); err != nil {
+			return models.Product{}, err
+		}
+
+		if err := r.replaceVariantPrices(ctx, tx, variantID, req.Prices); err != nil {
+			return models.Product{}, err
+		}
+
+		if err := r.recordStockChange(ctx, tx, productID, &variantID, 0, req.StockQuantity, models.StockReasonInitial); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, now, productID))
+	})
+}
+
+// UpdateVariant replaces every field of the variant and bumps the product version.
+func (r *ProductRepository) UpdateVariant(ctx context.Context, productID, variantID int, req models.VariantRequest, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		if err := r.checkOptionNames(ctx, tx, productID, variantID, req.Options); err != nil {
+			return models.Product{}, err
+		}
+
+		options, err := json.Marshal(req.Options)
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		stock, reserved, err := r.currentStock(ctx, tx, productID, &variantID)
+		if err != nil {
+			return models.Product{}, err
+		}
+		if err := belowReserved(req.StockQuantity, reserved); err != nil {
+			return models.Product{}, err
+		}
+
+		now := time.Now()
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE product_variants
+			SET sku = NULLIF($1, ''), options = $2::jsonb, stock_quantity = $3, updated_at = $4
+			WHERE id = $5 AND product_id = $6
+		`, req.SKU, string(options), req.StockQuantity, now, variantID, productID); err != nil {
+			return models.Product{}, err
+		}
+
+		if err := r.replaceVariantPrices(ctx, tx, variantID, req.Prices); err != nil {
+			return models.Product{}, err
+		}
+
+		if err := r.recordStockChange(ctx, tx, productID, &variantID, stock, req.StockQuantity, models.StockReasonCorrection); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, now, productID))
+	})
+}
+
+func (r *ProductRepository) DeleteVariant(ctx context.Context, productID, variantID int, expectedVersion *int) (*models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	return r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		_, reserved, err := r.currentStock(ctx, tx, productID, &variantID)
+		if err != nil {
+			return models.Product{}, err
+		}
+		if reserved > 0 {
+			return models.Product{}, apperrors.Conflict("a variante %d tem %d unidades reservadas", variantID, reserved)
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_variants WHERE id = $1 AND product_id = $2`, variantID, productID); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, time.Now(), productID))
+	})
+}
+
+// checkOptionNames compares options with those of another variant of the
+// product. Callers hold the product row lock, so the variants cannot change
+// underneath the check.
+func (r *ProductRepository) checkOptionNames(ctx context.Context, tx *sql.Tx, productID, variantID int, options map[string]string) error {
+	var existing []byte
+	err := tx.QueryRowContext(ctx, `
+		SELECT options FROM product_variants
+		WHERE product_id = $1 AND id <> $2
+		LIMIT 1
+	`, productID, variantID).Scan(&existing)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var other map[string]string
+	if err := json.Unmarshal(existing, &other); err != nil {
+		return err
+	}
+	return sameOptionNames(other, options)
+}
+
+func (r *ProductRepository) replaceVariantPrices(ctx context.Context, tx *sql.Tx, variantID int, prices map[string]money.Money) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM product_variant_prices WHERE variant_id = $1`, variantID); err != nil {
+		return translateError(err)
+	}
+
+	for currency, amount := range prices {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO product_variant_prices (variant_id, currency, amount)
+			VALUES ($1, $2, $3)
+		`, variantID, currency, amount); err != nil {
+			return translateError(err)
+		}
+	}
+
+	return nil
+}
+
+// sameOptionNames enforces that all the variants of a product use the same
+// option axes, given the options of one existing variant.
+func sameOptionNames(existing, options map[string]string) error {
+	same := len(existing) == len(options)
+	for name := range existing {
+		if _, ok := options[name]; !ok {
+			same = false
+		}
+	}
+	if !same {
+		return apperrors.Validation("as variantes deste produto usam as opções %s", strings.Join(optionNames(existing), ", "))
+	}
+	return nil
+}
+
+func optionNames(options map[string]string) []string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
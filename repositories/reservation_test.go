@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// createStocked creates a product priced in BRL with stock units and deletes
+// it when the test ends.
+func createStocked(t *testing.T, store ProductStore, name string, stock int) *models.Product {
+	t.Helper()
+	product, err := store.Create(context.Background(), models.CreateProductRequest{
+		Name:          name,
+		Prices:        map[string]money.Money{"BRL": money.MustParse("10.00")},
+		StockQuantity: stock,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Delete(context.Background(), product.ID, nil) })
+	return product
+}
+
+func expectStock(t *testing.T, store ProductStore, id, stock, reserved int) {
+	t.Helper()
+	product, err := store.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if product.StockQuantity != stock || product.ReservedQuantity != reserved || product.TotalAvailable != stock-reserved {
+		t.Errorf("stock = %d, reserved = %d, available = %d; want %d, %d, %d",
+			product.StockQuantity, product.ReservedQuantity, product.TotalAvailable, stock, reserved, stock-reserved)
+	}
+}
+
+func expectConflict(t *testing.T, err error) {
+	t.Helper()
+	if !errors.Is(err, apperrors.ErrConflict) {
+		t.Errorf("error = %v, want conflict", err)
+	}
+}
+
+func testReservations(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("over-reservation", func(t *testing.T) {
+		product := createStocked(t, store, "Reserva", 5)
+		if _, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 3}, expiresAt); err != nil {
+			t.Fatal(err)
+		}
+		_, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 3}, expiresAt)
+		expectConflict(t, err)
+		if _, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 2}, expiresAt); err != nil {
+			t.Fatal(err)
+		}
+		expectStock(t, store, product.ID, 5, 5)
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		product := createStocked(t, store, "Reserva", 5)
+		reservation, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 2}, expiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		confirmed, _, err := store.ConfirmReservation(ctx, reservation.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if confirmed.Status != models.ReservationConfirmed {
+			t.Errorf("status = %s, want confirmed", confirmed.Status)
+		}
+		expectStock(t, store, product.ID, 3, 0)
+
+		_, _, err = store.ConfirmReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+		_, err = store.CancelReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+		expectStock(t, store, product.ID, 3, 0)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		product := createStocked(t, store, "Reserva", 5)
+		reservation, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 2}, expiresAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cancelled, err := store.CancelReservation(ctx, reservation.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cancelled.Status != models.ReservationCancelled {
+			t.Errorf("status = %s, want cancelled", cancelled.Status)
+		}
+		expectStock(t, store, product.ID, 5, 0)
+
+		_, err = store.CancelReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+		_, _, err = store.ConfirmReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+		expectStock(t, store, product.ID, 5, 0)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		product := createStocked(t, store, "Reserva", 5)
+		reservation, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 4}, time.Now().Add(-time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Until the sweeper runs, the expired reservation still holds the stock
+		// but can't be confirmed.
+		_, _, err = store.ConfirmReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+		expectStock(t, store, product.ID, 5, 4)
+
+		expired, err := store.ExpireReservations(ctx, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expired < 1 {
+			t.Errorf("expired = %d, want at least 1", expired)
+		}
+		got, err := store.GetReservation(ctx, reservation.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != models.ReservationExpired {
+			t.Errorf("status = %s, want expired", got.Status)
+		}
+		expectStock(t, store, product.ID, 5, 0)
+
+		_, err = store.CancelReservation(ctx, reservation.ID)
+		expectConflict(t, err)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := store.ReserveStock(ctx, 1<<30, models.ReservationRequest{Quantity: 1}, expiresAt)
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("ReserveStock error = %v, want not found", err)
+		}
+		_, _, err = store.ConfirmReservation(ctx, 1<<40)
+		if !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("ConfirmReservation error = %v, want not found", err)
+		}
+	})
+}
+
+// testConcurrentReservations reserves one unit at a time from many
+// goroutines: exactly the stock must be reserved, never more.
+func testConcurrentReservations(t *testing.T, store ProductStore) {
+	const stock, attempts = 10, 40
+	product := createStocked(t, store, "Reserva concorrente", stock)
+	expiresAt := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.ReserveStock(context.Background(), product.ID, models.ReservationRequest{Quantity: 1}, expiresAt)
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	reserved := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			reserved++
+		case !errors.Is(err, apperrors.ErrConflict):
+			t.Errorf("ReserveStock error = %v, want nil or conflict", err)
+		}
+	}
+	if reserved != stock {
+		t.Errorf("%d reservations succeeded, want %d", reserved, stock)
+	}
+	expectStock(t, store, product.ID, stock, stock)
+}
+
+func TestMemoryReservations(t *testing.T) {
+	testReservations(t, NewMemoryProductRepository())
+}
+
+func TestMemoryConcurrentReservations(t *testing.T) {
+	testConcurrentReservations(t, NewMemoryProductRepository())
+}
+
+func TestPostgresReservations(t *testing.T) {
+	testReservations(t, postgresStore(t))
+}
+
+func TestPostgresConcurrentReservations(t *testing.T) {
+	testConcurrentReservations(t, postgresStore(t))
+}
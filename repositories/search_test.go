@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// createListed creates a product that is taken out of its category before it
+// is deleted on cleanup, so the category can be deleted too.
+func createListed(t *testing.T, store ProductStore, req models.CreateProductRequest) *models.Product {
+	t.Helper()
+	if req.Prices == nil {
+		req.Prices = map[string]money.Money{"BRL": money.MustParse("10.00")}
+	}
+	product, err := store.Create(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		none := 0
+		store.Update(ctx, product.ID, models.UpdateProductRequest{CategoryID: &none}, nil)
+		store.Delete(ctx, product.ID, nil)
+	})
+	return product
+}
+
+// sameIDs reports whether a and b hold the same IDs in any order.
+func sameIDs(a, b []int) bool {
+	count := make(map[int]int)
+	for _, id := range a {
+		count[id]++
+	}
+	for _, id := range b {
+		count[id]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// testSearch checks that words match without accents and in the plural and
+// that matches in the name rank above matches in the description.
+func testSearch(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	maker := createListed(t, store, models.CreateProductRequest{
+		Name: "Cafeteira Expressa Bialetti", Description: "Prepara café expresso em poucos minutos",
+	})
+	grinder := createListed(t, store, models.CreateProductRequest{
+		Name: "Moedor de Café Bialetti", Description: "Moedor manual para grãos",
+	})
+	mug := createListed(t, store, models.CreateProductRequest{
+		Name: "Caneca Térmica Bialetti", Description: "Mantém o café quente",
+	})
+	trashed := createListed(t, store, models.CreateProductRequest{
+		Name: "Café em Grãos Bialetti", Description: "Torra média",
+	})
+	if err := store.Delete(ctx, trashed.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string) []models.ProductSearchResult {
+		t.Helper()
+		results, err := store.Search(ctx, models.ProductSearch{Query: query, Currency: "BRL"}, models.SearchNextToken{Limit: 50})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var own []models.ProductSearchResult
+		for _, result := range results {
+			if strings.Contains(result.Name, "Bialetti") {
+				own = append(own, result)
+			}
+		}
+		return own
+	}
+	ids := func(results []models.ProductSearchResult) []int {
+		var ids []int
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+
+	// Only the grinder has café in its name; the others match in the
+	// description and rank below it, in any order.
+	tests := []struct {
+		query string
+		first int
+		want  []int
+	}{
+		{"CAFE bialetti", grinder.ID, []int{maker.ID, mug.ID}},
+		{"café", grinder.ID, []int{maker.ID, mug.ID}},
+		{"cafeteiras", maker.ID, nil},
+		{"moedor de cafe", grinder.ID, nil},
+		{"termica", mug.ID, nil},
+		{"bialetti chaleira", 0, nil},
+	}
+	for _, tt := range tests {
+		results := search(tt.query)
+		got := ids(results)
+		want := tt.want
+		if tt.first != 0 {
+			want = append([]int{tt.first}, want...)
+		}
+		if len(got) != len(want) || (len(got) > 0 && got[0] != tt.first) || !sameIDs(got, want) {
+			t.Errorf("%q: %v, want %v", tt.query, got, want)
+			continue
+		}
+		for i := 1; i < len(results); i++ {
+			if results[i-1].Rank < results[i].Rank {
+				t.Errorf("%q: rank %v before %v", tt.query, results[i-1].Rank, results[i].Rank)
+			}
+		}
+		if len(results) > 1 && results[0].Rank <= results[1].Rank {
+			t.Errorf("%q: name match ranked %v, want above the description match %v", tt.query, results[0].Rank, results[1].Rank)
+		}
+	}
+
+	for _, result := range search("cafe") {
+		if result.ID == grinder.ID && !strings.Contains(result.Highlights.Name, "<mark>Café</mark>") {
+			t.Errorf("highlighted name = %s, want Café marked", result.Highlights.Name)
+		}
+	}
+
+	usd, err := store.Search(ctx, models.ProductSearch{Query: "bialetti", Currency: "USD"}, models.SearchNextToken{Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range usd {
+		if strings.Contains(result.Name, "Bialetti") {
+			t.Errorf("USD search returned %d, which has no USD price", result.ID)
+		}
+	}
+}
+
+func TestMemorySearch(t *testing.T) {
+	testSearch(t, NewMemoryProductRepository())
+}
+
+func TestPostgresSearch(t *testing.T) {
+	testSearch(t, postgresStore(t))
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// GetLowStock lists the products that are low or out of stock, the ones out
+// of stock first and then the furthest below their reorder point.
+func (r *ProductRepository) GetLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	conditions := ` AND ` + stockStatusExpr + ` <> 'in_stock'`
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = ` AND ` + stockStatusExpr + ` = $1`
+		args = append(args, filter.Status)
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE deleted_at IS NULL` + conditions + `
+		ORDER BY ` + availableStockExpr + ` > 0, ` + availableStockExpr + ` - COALESCE(reorder_point, 0), id`
+
+	return r.queryProducts(ctx, query, args...)
+}
+
+// StockStatusChanges returns the products whose stock status differs from
+// the one last acknowledged with AcknowledgeStockStatus.
+func (r *ProductRepository) StockStatusChanges(ctx context.Context) ([]models.StockStatusChange, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	query := `
+		SELECT ` + productColumns + `, stock_alert_status
+		FROM products
+		WHERE deleted_at IS NULL AND ` + stockStatusExpr + ` <> stock_alert_status
+		ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var changes []models.StockStatusChange
+	for rows.Next() {
+		var previous string
+		product, err := scanProduct(extraColumns{rows, []interface{}{&previous}})
+		if err != nil {
+			return nil, translateError(err)
+		}
+		changes = append(changes, models.StockStatusChange{Product: product, Previous: previous})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return changes, nil
+}
+
+// AcknowledgeStockStatus records status as handled by the stock alerts. It
+// doesn't change the product version.
+func (r *ProductRepository) AcknowledgeStockStatus(ctx context.Context, productID int, status string) error {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE products SET stock_alert_status = $1 WHERE id = $2`, status, productID)
+	return translateError(err)
+}
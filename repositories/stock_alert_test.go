@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/models"
+	"github.com/seuusuario/api-rest-go/money"
+)
+
+// testLowStock checks the stock status at the reorder point boundaries and the
+// low stock report built from it.
+func testLowStock(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		stock        int
+		reserved     int
+		reorderPoint int
+		want         string
+	}{
+		{"above the reorder point", 6, 0, 5, models.StockStatusInStock},
+		{"at the reorder point", 5, 0, 5, models.StockStatusLow},
+		{"below the reorder point", 4, 0, 5, models.StockStatusLow},
+		{"reserved down to the reorder point", 8, 3, 5, models.StockStatusLow},
+		{"one unit without reorder point", 1, 0, 0, models.StockStatusInStock},
+		{"zero", 0, 0, 5, models.StockStatusOutOfStock},
+		{"zero without reorder point", 0, 0, 0, models.StockStatusOutOfStock},
+		{"all reserved", 3, 3, 0, models.StockStatusOutOfStock},
+	}
+
+	ids := make(map[int]string)
+	for _, tt := range tests {
+		product, err := store.Create(ctx, models.CreateProductRequest{
+			Name:          "Alerta " + tt.name,
+			Prices:        map[string]money.Money{"BRL": money.MustParse("1.00")},
+			StockQuantity: tt.stock,
+			ReorderPoint:  tt.reorderPoint,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := product.ID
+		t.Cleanup(func() { store.Delete(context.Background(), id, nil) })
+		if tt.reserved > 0 {
+			if _, err := store.ReserveStock(ctx, id, models.ReservationRequest{Quantity: tt.reserved}, time.Now().Add(time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		product, err = store.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if product.StockStatus != tt.want {
+			t.Errorf("%s: stock_status = %s, want %s", tt.name, product.StockStatus, tt.want)
+		}
+		ids[id] = tt.want
+	}
+
+	for _, status := range []string{"", models.StockStatusLow, models.StockStatusOutOfStock} {
+		report, err := store.GetLowStock(ctx, models.LowStockFilter{Status: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []models.Product
+		for _, product := range report {
+			if _, ok := ids[product.ID]; ok {
+				got = append(got, product)
+			}
+		}
+		want := 0
+		for _, s := range ids {
+			if s != models.StockStatusInStock && (status == "" || s == status) {
+				want++
+			}
+		}
+		if len(got) != want {
+			t.Errorf("status %q: %d products, want %d", status, len(got), want)
+		}
+
+		// Out of stock first, then the furthest below the reorder point.
+		for i := 1; i < len(got); i++ {
+			a, b := got[i-1], got[i]
+			if a.StockStatus == models.StockStatusInStock || (status != "" && a.StockStatus != status) {
+				t.Errorf("status %q: product %d is %s", status, a.ID, a.StockStatus)
+			}
+			if a.TotalAvailable > 0 && b.TotalAvailable <= 0 {
+				t.Errorf("status %q: product %d listed before out of stock product %d", status, a.ID, b.ID)
+			}
+			if (a.TotalAvailable > 0) == (b.TotalAvailable > 0) && a.TotalAvailable-a.ReorderPoint > b.TotalAvailable-b.ReorderPoint {
+				t.Errorf("status %q: product %d listed before %d, which is further below its reorder point", status, a.ID, b.ID)
+			}
+		}
+	}
+}
+
+func TestMemoryLowStock(t *testing.T) {
+	testLowStock(t, NewMemoryProductRepository())
+}
+
+func TestPostgresLowStock(t *testing.T) {
+	testLowStock(t, postgresStore(t))
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// ledgerBalance sums the movements of a product, or of one of its variants.
+func ledgerBalance(t *testing.T, store ProductStore, productID int, variantID *int) int {
+	t.Helper()
+	movements, err := store.GetStockMovements(context.Background(), productID, models.StockMovementFilter{VariantID: variantID}, models.NextTokenRequest{Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := 0
+	for _, movement := range movements {
+		if (movement.VariantID == nil) == (variantID == nil) {
+			sum += movement.Quantity
+		}
+	}
+	return sum
+}
+
+func expectBalanced(t *testing.T, store ProductStore, productID int) {
+	t.Helper()
+	product, err := store.GetByID(context.Background(), productID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := ledgerBalance(t, store, productID, nil); sum != product.StockQuantity {
+		t.Errorf("ledger = %d, stock = %d", sum, product.StockQuantity)
+	}
+	for _, variant := range product.Variants {
+		variantID := variant.ID
+		if sum := ledgerBalance(t, store, productID, &variantID); sum != variant.StockQuantity {
+			t.Errorf("variant %d: ledger = %d, stock = %d", variant.ID, sum, variant.StockQuantity)
+		}
+	}
+
+	discrepancies, err := store.ReconcileStock(context.Background(), models.ReconciliationFilter{ProductID: productID, DiscrepanciesOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(discrepancies) != 0 {
+		t.Errorf("discrepancies = %+v, want none", discrepancies)
+	}
+}
+
+// testStockLedger checks that every way of changing the stock keeps the
+// ledger in balance. drift changes the stored stock of a product by delta
+// without a movement.
+func testStockLedger(t *testing.T, store ProductStore, drift func(t *testing.T, productID, delta int)) {
+	ctx := context.Background()
+
+	t.Run("movements", func(t *testing.T) {
+		product := createStocked(t, store, "Livro", 10)
+		for _, req := range []models.StockMovementRequest{
+			{Quantity: 5, Reason: models.StockReasonReceipt, Reference: "NF-1"},
+			{Quantity: -3, Reason: models.StockReasonSale},
+			{Quantity: -2, Reason: models.StockReasonCorrection},
+		} {
+			movement, _, err := store.RecordStockMovement(ctx, product.ID, req, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if movement.Quantity != req.Quantity || movement.Reason != req.Reason {
+				t.Errorf("movement = %+v, want %+v", movement, req)
+			}
+		}
+		expectStock(t, store, product.ID, 10, 0)
+		expectBalanced(t, store, product.ID)
+
+		_, _, err := store.RecordStockMovement(ctx, product.ID, models.StockMovementRequest{Quantity: -11, Reason: models.StockReasonSale}, nil)
+		expectConflict(t, err)
+		expectBalanced(t, store, product.ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		product := createStocked(t, store, "Livro", 10)
+		stock := 7
+		if _, err := store.Update(ctx, product.ID, models.UpdateProductRequest{StockQuantity: &stock}, nil); err != nil {
+			t.Fatal(err)
+		}
+		movements, err := store.GetStockMovements(ctx, product.ID, models.StockMovementFilter{Reason: models.StockReasonCorrection}, models.NextTokenRequest{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(movements) != 1 || movements[0].Quantity != -3 || movements[0].Balance != 7 {
+			t.Errorf("corrections = %+v, want one of -3", movements)
+		}
+		expectBalanced(t, store, product.ID)
+
+		// Reserved units can't be taken out of the stock by an update.
+		if _, err := store.ReserveStock(ctx, product.ID, models.ReservationRequest{Quantity: 4}, time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		stock = 3
+		_, err = store.Update(ctx, product.ID, models.UpdateProductRequest{StockQuantity: &stock}, nil)
+		expectConflict(t, err)
+		_, _, err = store.RecordStockMovement(ctx, product.ID, models.StockMovementRequest{Quantity: -4, Reason: models.StockReasonLoss}, nil)
+		expectConflict(t, err)
+		expectStock(t, store, product.ID, 7, 4)
+		expectBalanced(t, store, product.ID)
+	})
+
+	t.Run("variants", func(t *testing.T) {
+		product := createStocked(t, store, "Camiseta", 0)
+		product, err := store.CreateVariant(ctx, product.ID, models.VariantRequest{Options: map[string]string{"tamanho": "M"}, StockQuantity: 4}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		variantID := product.Variants[0].ID
+
+		_, _, err = store.RecordStockMovement(ctx, product.ID, models.StockMovementRequest{Quantity: 2, Reason: models.StockReasonReceipt}, nil)
+		if !errors.Is(err, apperrors.ErrValidation) {
+			t.Errorf("movement without variant_id error = %v, want validation", err)
+		}
+		if _, _, err := store.RecordStockMovement(ctx, product.ID, models.StockMovementRequest{VariantID: &variantID, Quantity: 2, Reason: models.StockReasonReceipt}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if sum := ledgerBalance(t, store, product.ID, &variantID); sum != 6 {
+			t.Errorf("variant ledger = %d, want 6", sum)
+		}
+		expectBalanced(t, store, product.ID)
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		product := createStocked(t, store, "Livro", 10)
+		drift(t, product.ID, 3)
+
+		discrepancies, err := store.ReconcileStock(ctx, models.ReconciliationFilter{ProductID: product.ID, DiscrepanciesOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := models.StockReconciliation{ProductID: product.ID, StockQuantity: 13, LedgerQuantity: 10, Difference: 3}
+		if len(discrepancies) != 1 || discrepancies[0] != want {
+			t.Errorf("discrepancies = %+v, want %+v", discrepancies, want)
+		}
+	})
+}
+
+func TestMemoryStockLedger(t *testing.T) {
+	store := NewMemoryProductRepository()
+	testStockLedger(t, store, func(t *testing.T, productID, delta int) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		product := store.products[productID]
+		product.StockQuantity += delta
+		store.products[productID] = product
+	})
+}
+
+func TestPostgresStockLedger(t *testing.T) {
+	store := postgresStore(t)
+	testStockLedger(t, store, func(t *testing.T, productID, delta int) {
+		_, err := store.db.Exec(`UPDATE products SET stock_quantity = stock_quantity + $1 WHERE id = $2`, delta, productID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
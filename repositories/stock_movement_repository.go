@@ -0,0 +1,281 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/audit"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+// RecordStockMovement applies the movement to the stock of the product, or of
+// one of its variants, and appends it to the ledger in the same transaction.
+// Movements that would leave less stock than is reserved are rejected.
+func (r *ProductRepository) RecordStockMovement(ctx context.Context, productID int, req models.StockMovementRequest, expectedVersion *int) (*models.StockMovement, *models.Product, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	var movement models.StockMovement
+	product, err := r.mutate(ctx, productID, expectedVersion, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		if req.VariantID == nil {
+			var hasVariants bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM product_variants WHERE product_id = $1)`, productID).Scan(&hasVariants); err != nil {
+				return models.Product{}, err
+			}
+			if hasVariants {
+				return models.Product{}, apperrors.Validation("o produto %d tem variantes; informe variant_id", productID)
+			}
+		}
+
+		current, reserved, err := r.currentStock(ctx, tx, productID, req.VariantID)
+		if err != nil {
+			return models.Product{}, err
+		}
+
+		balance := current + req.Quantity
+		if balance < reserved {
+			return models.Product{}, insufficientStock(current - reserved)
+		}
+
+		now := time.Now()
+		if err := r.setStock(ctx, tx, productID, req.VariantID, balance, now); err != nil {
+			return models.Product{}, err
+		}
+
+		movement = models.StockMovement{
+			ProductID: productID,
+			VariantID: req.VariantID,
+			Quantity:  req.Quantity,
+			Balance:   balance,
+			Reason:    req.Reason,
+			Reference: req.Reference,
+		}
+		if err := r.insertStockMovement(ctx, tx, &movement); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, now, productID))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &movement, product, nil
+}
+
+// currentStock reads the stock and the reserved quantity of the product or of
+// one of its variants. Callers hold the product row lock.
+func (r *ProductRepository) currentStock(ctx context.Context, tx *sql.Tx, productID int, variantID *int) (int, int, error) {
+	var stock, reserved int
+	if variantID == nil {
+		err := tx.QueryRowContext(ctx, `SELECT COALESCE(stock_quantity, 0), reserved_quantity FROM products WHERE id = $1`, productID).Scan(&stock, &reserved)
+		return stock, reserved, err
+	}
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT stock_quantity, reserved_quantity FROM product_variants
+		WHERE id = $1 AND product_id = $2
+	`, *variantID, productID).Scan(&stock, &reserved)
+	if err == sql.ErrNoRows {
+		return 0, 0, apperrors.NotFound("variante com ID %d não encontrada no produto %d", *variantID, productID)
+	}
+	return stock, reserved, err
+}
+
+func insufficientStock(available int) error {
+	return apperrors.Conflict("estoque insuficiente: %d unidades disponíveis", available)
+}
+
+// belowReserved rejects setting the stock below what active reservations hold.
+func belowReserved(stock, reserved int) error {
+	if stock < reserved {
+		return apperrors.Conflict("o estoque não pode ficar abaixo das %d unidades reservadas", reserved)
+	}
+	return nil
+}
+
+func (r *ProductRepository) setStock(ctx context.Context, tx *sql.Tx, productID int, variantID *int, stock int, now time.Time) error {
+	if variantID == nil {
+		_, err := tx.ExecContext(ctx, `UPDATE products SET stock_quantity = $1 WHERE id = $2`, stock, productID)
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE product_variants SET stock_quantity = $1, updated_at = $2
+		WHERE id = $3 AND product_id = $4
+	`, stock, now, *variantID, productID)
+	return err
+}
+
+// recordStockChange adds the movement that takes the stock from before to
+// after, so writes that set the stock directly keep the ledger in step.
+func (r *ProductRepository) recordStockChange(ctx context.Context, tx *sql.Tx, productID int, variantID *int, before, after int, reason string) error {
+	if before == after {
+		return nil
+	}
+
+	return r.insertStockMovement(ctx, tx, &models.StockMovement{
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  after - before,
+		Balance:   after,
+		Reason:    reason,
+	})
+}
+
+func (r *ProductRepository) insertStockMovement(ctx context.Context, tx *sql.Tx, movement *models.StockMovement) error {
+	movement.Actor = audit.ActorFromContext(ctx)
+	movement.RequestID = audit.RequestIDFromContext(ctx)
+	movement.CreatedAt = time.Now()
+
+	return translateError(tx.QueryRowContext(ctx, `
+		INSERT INTO stock_movements (product_id, variant_id, quantity, balance, reason, reference, actor, request_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''), $9)
+		RETURNING id
+	`, movement.ProductID, movement.VariantID, movement.Quantity, movement.Balance, movement.Reason,
+		movement.Reference, movement.Actor, movement.RequestID, movement.CreatedAt).Scan(&movement.ID))
+}
+
+func (r *ProductRepository) GetStockMovements(ctx context.Context, productID int, filter models.StockMovementFilter, nextToken models.NextTokenRequest) ([]models.StockMovement, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	query := `
+		SELECT id, product_id, variant_id, quantity, balance, reason, COALESCE(reference, ''), actor, COALESCE(request_id, ''), created_at
+		FROM stock_movements
+		WHERE product_id = $1
+	`
+	args := []interface{}{productID}
+
+	if filter.Reason != "" {
+		args = append(args, filter.Reason)
+		query += fmt.Sprintf(" AND reason = $%d", len(args))
+	}
+	if filter.VariantID != nil {
+		args = append(args, *filter.VariantID)
+		query += fmt.Sprintf(" AND variant_id = $%d", len(args))
+	}
+
+	if nextToken.Row > 0 {
+		args = append(args, nextToken.Row)
+		if nextToken.Order == "asc" {
+			query += fmt.Sprintf(" AND id > $%d", len(args))
+		} else {
+			query += fmt.Sprintf(" AND id < $%d", len(args))
+		}
+	}
+
+	if nextToken.Order == "asc" {
+		query += " ORDER BY id ASC"
+	} else {
+		query += " ORDER BY id DESC"
+	}
+
+	limit := nextToken.Limit
+	if limit == 0 {
+		limit = 10
+	}
+	query += fmt.Sprintf(" LIMIT $%d", len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var movements []models.StockMovement
+	for rows.Next() {
+		var movement models.StockMovement
+		var variantID sql.NullInt64
+		err := rows.Scan(
+			&movement.ID,
+			&movement.ProductID,
+			&variantID,
+			&movement.Quantity,
+			&movement.Balance,
+			&movement.Reason,
+			&movement.Reference,
+			&movement.Actor,
+			&movement.RequestID,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		if variantID.Valid {
+			id := int(variantID.Int64)
+			movement.VariantID = &id
+		}
+		movements = append(movements, movement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return movements, nil
+}
+
+// ReconcileStock compares the stock of every product and variant with the sum
+// of its ledger. Products in the trash are checked too, since their stock is
+// kept for a restore.
+func (r *ProductRepository) ReconcileStock(ctx context.Context, filter models.ReconciliationFilter) ([]models.StockReconciliation, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Filter)
+	defer cancel()
+
+	query := `
+		SELECT product_id, variant_id, stock_quantity, ledger_quantity
+		FROM (
+			SELECT p.id AS product_id, NULL::int AS variant_id, COALESCE(p.stock_quantity, 0) AS stock_quantity,
+				COALESCE((
+					SELECT SUM(m.quantity) FROM stock_movements m
+					WHERE m.product_id = p.id AND m.variant_id IS NULL
+				), 0) AS ledger_quantity
+			FROM products p
+			WHERE $1 = 0 OR p.id = $1
+			UNION ALL
+			SELECT v.product_id, v.id, v.stock_quantity,
+				COALESCE((
+					SELECT SUM(m.quantity) FROM stock_movements m
+					WHERE m.variant_id = v.id
+				), 0)
+			FROM product_variants v
+			WHERE $1 = 0 OR v.product_id = $1
+		) AS stock
+		WHERE NOT $2 OR stock_quantity <> ledger_quantity
+		ORDER BY product_id, variant_id NULLS FIRST
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.ProductID, filter.DiscrepanciesOnly)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var results []models.StockReconciliation
+	for rows.Next() {
+		var result models.StockReconciliation
+		var variantID sql.NullInt64
+		if err := rows.Scan(&result.ProductID, &variantID, &result.StockQuantity, &result.LedgerQuantity); err != nil {
+			return nil, translateError(err)
+		}
+		if variantID.Valid {
+			id := int(variantID.Int64)
+			result.VariantID = &id
+		}
+		result.Difference = result.StockQuantity - result.LedgerQuantity
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	if filter.ProductID != 0 && len(results) == 0 && !filter.DiscrepanciesOnly {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", filter.ProductID)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,357 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/audit"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+const reservationColumns = `id, product_id, variant_id, quantity, status, COALESCE(reference, ''), actor, expires_at, created_at, updated_at`
+
+// Reservations only change the reserved quantities, which are not part of the
+// product version, so they take a key share lock on the product instead of the
+// update lock of mutate. Every reservation write locks the product before the
+// reservation and variant rows, the same order mutate uses.
+
+// ReserveStock holds quantity units until expiresAt. The conditional update
+// only succeeds while enough stock is available, so concurrent reservations
+// never hold more than the stock.
+func (r *ProductRepository) ReserveStock(ctx context.Context, productID int, req models.ReservationRequest, expiresAt time.Time) (*models.StockReservation, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer tx.Rollback()
+
+	var hasVariants bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+		FROM products p
+		WHERE p.id = $1 AND p.deleted_at IS NULL
+		FOR KEY SHARE OF p
+	`, productID).Scan(&hasVariants)
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("produto com ID %d não encontrado", productID)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if req.VariantID == nil && hasVariants {
+		return nil, apperrors.Validation("o produto %d tem variantes; informe variant_id", productID)
+	}
+
+	var result sql.Result
+	if req.VariantID == nil {
+		result, err = tx.ExecContext(ctx, `
+			UPDATE products SET reserved_quantity = reserved_quantity + $1
+			WHERE id = $2 AND stock_quantity - reserved_quantity >= $1
+		`, req.Quantity, productID)
+	} else {
+		result, err = tx.ExecContext(ctx, `
+			UPDATE product_variants SET reserved_quantity = reserved_quantity + $1
+			WHERE id = $2 AND product_id = $3 AND stock_quantity - reserved_quantity >= $1
+		`, req.Quantity, *req.VariantID, productID)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		stock, reserved, err := r.currentStock(ctx, tx, productID, req.VariantID)
+		if err != nil {
+			return nil, translateError(err)
+		}
+		return nil, insufficientStock(stock - reserved)
+	}
+
+	now := time.Now()
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, `
+		INSERT INTO stock_reservations (product_id, variant_id, quantity, status, reference, actor, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $8)
+		RETURNING `+reservationColumns,
+		productID, req.VariantID, req.Quantity, models.ReservationActive, req.Reference, audit.ActorFromContext(ctx), expiresAt, now))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &reservation, nil
+}
+
+func (r *ProductRepository) GetReservation(ctx context.Context, id int64) (*models.StockReservation, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Read)
+	defer cancel()
+
+	reservation, err := scanReservation(r.db.QueryRowContext(ctx, `SELECT `+reservationColumns+` FROM stock_reservations WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, apperrors.NotFound("reserva com ID %d não encontrada", id)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &reservation, nil
+}
+
+// ConfirmReservation turns an active reservation into a sale: the reserved
+// units leave the stock and a sale movement is added to the ledger. Unlike the
+// other reservation writes it changes the product version.
+func (r *ProductRepository) ConfirmReservation(ctx context.Context, id int64) (*models.StockReservation, *models.Product, error) {
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	product, err := r.mutate(ctx, reservation.ProductID, nil, false, models.HistoryActionUpdate, func(tx *sql.Tx) (models.Product, error) {
+		now := time.Now()
+		confirmed, err := r.closeReservation(ctx, tx, id, models.ReservationConfirmed, now)
+		if err != nil {
+			return models.Product{}, err
+		}
+		reservation = confirmed
+
+		stock, _, err := r.currentStock(ctx, tx, reservation.ProductID, reservation.VariantID)
+		if err != nil {
+			return models.Product{}, err
+		}
+		if err := r.release(ctx, tx, reservation, now); err != nil {
+			return models.Product{}, err
+		}
+		if err := r.setStock(ctx, tx, reservation.ProductID, reservation.VariantID, stock-reservation.Quantity, now); err != nil {
+			return models.Product{}, err
+		}
+
+		reference := reservation.Reference
+		if reference == "" {
+			reference = fmt.Sprintf("reserva %d", reservation.ID)
+		}
+		if err := r.insertStockMovement(ctx, tx, &models.StockMovement{
+			ProductID: reservation.ProductID,
+			VariantID: reservation.VariantID,
+			Quantity:  -reservation.Quantity,
+			Balance:   stock - reservation.Quantity,
+			Reason:    models.StockReasonSale,
+			Reference: reference,
+		}); err != nil {
+			return models.Product{}, err
+		}
+
+		return scanProduct(tx.QueryRowContext(ctx, touchProductQuery, now, reservation.ProductID))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return reservation, product, nil
+}
+
+// CancelReservation releases the units held by an active reservation.
+func (r *ProductRepository) CancelReservation(ctx context.Context, id int64) (*models.StockReservation, error) {
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM products WHERE id = $1 FOR KEY SHARE`, reservation.ProductID); err != nil {
+		return nil, translateError(err)
+	}
+
+	now := time.Now()
+	reservation, err = r.closeReservation(ctx, tx, id, models.ReservationCancelled, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.release(ctx, tx, reservation, now); err != nil {
+		return nil, translateError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, translateError(err)
+	}
+
+	return reservation, nil
+}
+
+// ExpireReservations releases every active reservation that expired by now
+// and returns how many there were.
+func (r *ProductRepository) ExpireReservations(ctx context.Context, now time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx, r.timeouts.Write)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM products
+		WHERE id IN (
+			SELECT product_id FROM stock_reservations
+			WHERE status = 'active' AND expires_at <= $1
+		)
+		ORDER BY id
+		FOR KEY SHARE
+	`, now)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	var productIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, translateError(err)
+		}
+		productIDs = append(productIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, translateError(err)
+	}
+	if len(productIDs) == 0 {
+		return 0, nil
+	}
+
+	// Only the reservations of the locked products are expired; any other
+	// expiring meanwhile is left for the next run.
+	var expired int
+	err = tx.QueryRowContext(ctx, `
+		WITH expired AS (
+			UPDATE stock_reservations
+			SET status = 'expired', updated_at = $1
+			WHERE status = 'active' AND expires_at <= $1 AND product_id = ANY($2)
+			RETURNING product_id, variant_id, quantity
+		), released_products AS (
+			UPDATE products p
+			SET reserved_quantity = p.reserved_quantity - e.quantity
+			FROM (
+				SELECT product_id, SUM(quantity) AS quantity FROM expired
+				WHERE variant_id IS NULL
+				GROUP BY product_id
+			) e
+			WHERE p.id = e.product_id
+		), released_variants AS (
+			UPDATE product_variants v
+			SET reserved_quantity = v.reserved_quantity - e.quantity
+			FROM (
+				SELECT variant_id, SUM(quantity) AS quantity FROM expired
+				WHERE variant_id IS NOT NULL
+				GROUP BY variant_id
+			) e
+			WHERE v.id = e.variant_id
+		)
+		SELECT COUNT(*) FROM expired
+	`, now, pq.Array(productIDs)).Scan(&expired)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, translateError(err)
+	}
+
+	return expired, nil
+}
+
+// closeReservation moves an active reservation to status. Confirming also
+// requires the reservation not to have expired yet, even if the sweeper has
+// not released it.
+func (r *ProductRepository) closeReservation(ctx context.Context, tx *sql.Tx, id int64, status string, now time.Time) (*models.StockReservation, error) {
+	query := `
+		UPDATE stock_reservations SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = 'active'`
+	if status == models.ReservationConfirmed {
+		query += ` AND expires_at > $2`
+	}
+
+	reservation, err := scanReservation(tx.QueryRowContext(ctx, query+` RETURNING `+reservationColumns, status, now, id))
+	if err == sql.ErrNoRows {
+		current, err := scanReservation(tx.QueryRowContext(ctx, `SELECT `+reservationColumns+` FROM stock_reservations WHERE id = $1`, id))
+		if err != nil {
+			return nil, translateError(err)
+		}
+		return nil, reservationClosed(current, now)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &reservation, nil
+}
+
+// release gives the units held by reservation back to the available stock.
+func (r *ProductRepository) release(ctx context.Context, tx *sql.Tx, reservation *models.StockReservation, now time.Time) error {
+	if reservation.VariantID == nil {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE products SET reserved_quantity = reserved_quantity - $1
+			WHERE id = $2
+		`, reservation.Quantity, reservation.ProductID)
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE product_variants SET reserved_quantity = reserved_quantity - $1
+		WHERE id = $2
+	`, reservation.Quantity, *reservation.VariantID)
+	return err
+}
+
+func scanReservation(row rowScanner) (models.StockReservation, error) {
+	var reservation models.StockReservation
+	var variantID sql.NullInt64
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.ProductID,
+		&variantID,
+		&reservation.Quantity,
+		&reservation.Status,
+		&reservation.Reference,
+		&reservation.Actor,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+		&reservation.UpdatedAt,
+	)
+	if variantID.Valid {
+		id := int(variantID.Int64)
+		reservation.VariantID = &id
+	}
+	return reservation, err
+}
+
+// reservationClosed explains why a reservation can no longer be confirmed or
+// cancelled.
+func reservationClosed(reservation models.StockReservation, now time.Time) error {
+	switch {
+	case reservation.Status == models.ReservationConfirmed:
+		return apperrors.Conflict("a reserva %d já foi confirmada", reservation.ID)
+	case reservation.Status == models.ReservationCancelled:
+		return apperrors.Conflict("a reserva %d já foi cancelada", reservation.ID)
+	case reservation.Status == models.ReservationExpired, !reservation.ExpiresAt.After(now):
+		return apperrors.Conflict("a reserva %d expirou", reservation.ID)
+	}
+	return apperrors.Conflict("a reserva %d não está ativa", reservation.ID)
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func createCategory(t *testing.T, categories CategoryStore, slug, name string) *models.Category {
+	t.Helper()
+	category, err := categories.Create(context.Background(), models.CategoryRequest{Slug: slug, Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { categories.Delete(context.Background(), category.ID) })
+	return category
+}
+
+// testSuggest checks that incomplete, misspelled and unaccented words suggest
+// product and category names.
+func testSuggest(t *testing.T, store ProductStore, categories CategoryStore) {
+	ctx := context.Background()
+	category := createCategory(t, categories, "chaleiras-eletricas", "Chaleiras Elétricas")
+	kettle := createListed(t, store, models.CreateProductRequest{Name: "Chaleira Inox Tramontina", CategoryID: &category.ID})
+	trashed := createListed(t, store, models.CreateProductRequest{Name: "Chaleira Esmaltada Tramontina"})
+	if err := store.Delete(ctx, trashed.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"chaleira", []string{"product", "category"}},
+		{"chal", []string{"product", "category"}},
+		{"chaleria", []string{"product", "category"}},
+		{"eletricas", []string{"category"}},
+		{"tramontina", []string{"product"}},
+		{"xyzw", nil},
+	}
+	for _, tt := range tests {
+		suggestions, err := store.Suggest(ctx, tt.prefix, 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for i, suggestion := range suggestions {
+			if i > 0 && suggestion.Score > suggestions[i-1].Score {
+				t.Errorf("%q: score %v after %v", tt.prefix, suggestion.Score, suggestions[i-1].Score)
+			}
+			switch {
+			case suggestion.Type == "product" && suggestion.ID == trashed.ID:
+				t.Errorf("%q: suggested the deleted product", tt.prefix)
+			case suggestion.Type == "product" && suggestion.ID == kettle.ID,
+				suggestion.Type == "category" && suggestion.ID == category.ID:
+				got = append(got, suggestion.Type)
+			}
+		}
+		sort.Strings(got)
+		sort.Strings(tt.want)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q: suggested %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	suggestions, err := store.Suggest(ctx, "chaleira", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Score != 1 {
+		t.Errorf("limited suggestions = %+v, want one exact match", suggestions)
+	}
+}
+
+func TestMemorySuggest(t *testing.T) {
+	store := NewMemoryProductRepository()
+	testSuggest(t, store, NewMemoryCategoryRepository(store))
+}
+
+func TestPostgresSuggest(t *testing.T) {
+	store := postgresStore(t)
+	testSuggest(t, store, NewCategoryRepository(store.db, QueryTimeouts{}))
+}
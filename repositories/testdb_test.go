@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/seuusuario/api-rest-go/database"
+)
+
+// postgresStore returns a store on the database in TEST_DATABASE_URL,
+// migrating it first, and skips the test when the variable is not set. Tests
+// share the database, so they must only look at the rows they create.
+func postgresStore(t *testing.T) *ProductRepository {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL não definida")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewProductRepository(db, QueryTimeouts{})
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// QueryTimeouts holds the deadline applied to each kind of repository operation.
+// A zero value disables the deadline and only the caller's context applies.
+type QueryTimeouts struct {
+	Read    time.Duration
+	Write   time.Duration
+	Filter  time.Duration
+	Suggest time.Duration
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/seuusuario/api-rest-go/apperrors"
+	"github.com/seuusuario/api-rest-go/models"
+)
+
+func inTrash(t *testing.T, store ProductStore, id int) bool {
+	t.Helper()
+	deleted, err := store.GetDeleted(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, product := range deleted {
+		if product.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// testPurge checks that only products deleted before the cutoff are removed
+// for good.
+func testPurge(t *testing.T, store ProductStore) {
+	ctx := context.Background()
+	old := createStocked(t, store, "Antigo", 1)
+	recent := createStocked(t, store, "Recente", 1)
+	kept := createStocked(t, store, "Ativo", 1)
+
+	if err := store.Delete(ctx, old.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	cutoff := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	if err := store.Delete(ctx, recent.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	purged, err := store.PurgeDeleted(ctx, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, product := range purged {
+		if product.ID == recent.ID || product.ID == kept.ID {
+			t.Errorf("purged product %d, which was not in the trash before the cutoff", product.ID)
+		}
+		found = found || product.ID == old.ID
+	}
+	if !found {
+		t.Errorf("purged = %+v, want product %d", purged, old.ID)
+	}
+
+	if inTrash(t, store, old.ID) {
+		t.Errorf("product %d still in the trash", old.ID)
+	}
+	if !inTrash(t, store, recent.ID) {
+		t.Errorf("product %d left the trash", recent.ID)
+	}
+	if _, err := store.Restore(ctx, old.ID, nil); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("Restore of a purged product error = %v, want not found", err)
+	}
+	if _, err := store.GetByID(ctx, kept.ID); err != nil {
+		t.Errorf("active product: %v", err)
+	}
+
+	history, err := store.GetHistory(ctx, old.ID, models.NextTokenRequest{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) == 0 || history[0].Action != models.HistoryActionPurge {
+		t.Errorf("history = %+v, want the purge first", history)
+	}
+	if _, err := store.GetHistory(ctx, math.MaxInt32, models.NextTokenRequest{Limit: 10}); !errors.Is(err, apperrors.ErrNotFound) {
+		t.Errorf("history of a product that never existed error = %v, want not found", err)
+	}
+}
+
+func TestMemoryPurge(t *testing.T) {
+	testPurge(t, NewMemoryProductRepository())
+}
+
+func TestPostgresPurge(t *testing.T) {
+	testPurge(t, postgresStore(t))
+}
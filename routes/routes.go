@@ -2,17 +2,19 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/seuusuario/api-rest-go/audit"
 	_ "github.com/seuusuario/api-rest-go/docs"
 	"github.com/seuusuario/api-rest-go/handlers"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, productHandler *handlers.ProductHandler) {
+func SetupRoutes(router *gin.Engine, productHandler *handlers.ProductHandler, categoryHandler *handlers.CategoryHandler, actorHeader string) {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, If-Match, X-Request-ID, "+actorHeader)
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -22,6 +24,8 @@ func SetupRoutes(router *gin.Engine, productHandler *handlers.ProductHandler) {
 		c.Next()
 	})
 
+	router.Use(audit.Middleware(actorHeader))
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	v1 := router.Group("/api/v1")
@@ -30,12 +34,58 @@ func SetupRoutes(router *gin.Engine, productHandler *handlers.ProductHandler) {
 		{
 			products.GET("", productHandler.GetProducts)
 			products.GET("/filter", productHandler.FindByFilter)
+			products.GET("/search", productHandler.SearchProducts)
+			products.GET("/suggest", productHandler.SuggestProducts)
+			products.GET("/trash", productHandler.GetTrash)
+			products.GET("/sku/:sku", productHandler.GetProductBySKU)
+			products.GET("/barcode/:code", productHandler.GetProductByBarcode)
 			products.GET("/:id", productHandler.GetProduct)
 			products.POST("", productHandler.CreateProduct)
 			products.PUT("/:id", productHandler.UpdateProduct)
+			products.PATCH("/:id", productHandler.PatchProduct)
 			products.DELETE("/:id", productHandler.DeleteProduct)
+			products.POST("/:id/restore", productHandler.RestoreProduct)
+			products.GET("/:id/history", productHandler.GetProductHistory)
+			products.POST("/:id/tags", productHandler.AddProductTags)
+			products.DELETE("/:id/tags/:tag", productHandler.RemoveProductTag)
+			products.GET("/:id/variants", productHandler.GetProductVariants)
+			products.GET("/:id/variants/:variant_id", productHandler.GetProductVariant)
+			products.POST("/:id/variants", productHandler.CreateProductVariant)
+			products.PUT("/:id/variants/:variant_id", productHandler.UpdateProductVariant)
+			products.DELETE("/:id/variants/:variant_id", productHandler.DeleteProductVariant)
+			products.POST("/:id/images", productHandler.UploadProductImage)
+			products.PUT("/:id/images/order", productHandler.ReorderProductImages)
+			products.PUT("/:id/images/:image_id/primary", productHandler.SetPrimaryProductImage)
+			products.DELETE("/:id/images/:image_id", productHandler.DeleteProductImage)
+			products.POST("/:id/stock/movements", productHandler.RecordStockMovement)
+			products.GET("/:id/stock/movements", productHandler.GetStockMovements)
+			products.GET("/:id/stock/reconciliation", productHandler.GetStockReconciliation)
+			products.POST("/:id/reservations", productHandler.ReserveProductStock)
 			products.GET("/category/:category", productHandler.GetProductsByCategory)
 		}
+
+		reservations := v1.Group("/reservations")
+		{
+			reservations.GET("/:id", productHandler.GetReservation)
+			reservations.POST("/:id/confirm", productHandler.ConfirmReservation)
+			reservations.POST("/:id/cancel", productHandler.CancelReservation)
+		}
+
+		inventory := v1.Group("/inventory")
+		{
+			inventory.GET("/reconciliation", productHandler.GetInventoryReconciliation)
+			inventory.GET("/low-stock", productHandler.GetLowStock)
+		}
+
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryHandler.GetCategories)
+			categories.GET("/tree", categoryHandler.GetCategoryTree)
+			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.POST("", categoryHandler.CreateCategory)
+			categories.PUT("/:id", categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", categoryHandler.DeleteCategory)
+		}
 	}
 
 	router.GET("/health", func(c *gin.Context) {
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Storage keeps uploaded files. Keys are slash-separated relative paths chosen
+// by the caller, such as "products/1/3f2a.png".
+type Storage interface {
+	// Save stores content under key, replacing any previous file.
+	Save(ctx context.Context, key string, content io.Reader) error
+	// Delete removes the file; deleting a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients use to download the file.
+	URL(key string) string
+}